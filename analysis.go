@@ -0,0 +1,539 @@
+package bgammon
+
+// opponentNumber returns the number of the provided player's opponent.
+func opponentNumber(player int8) int8 {
+	if player == 1 {
+		return 2
+	}
+	return 1
+}
+
+// Anchors returns the spaces within the opponent's home board where the
+// provided player has two or more checkers.
+func (g *Game) Anchors(player int8) []int8 {
+	var anchors []int8
+	from, to := HomeRange(opponentNumber(player), g.Variant)
+	IterateSpaces(from, to, g.Variant, func(space int8, spaceCount int8) {
+		if PlayerCheckers(g.Board[space], player) >= 2 {
+			anchors = append(anchors, space)
+		}
+	})
+	return anchors
+}
+
+// IsHoldingGame returns whether the provided player is behind in the race
+// while holding a single advanced anchor on the opponent's 4 point, 5 point
+// or bar point. Tabula games never contain a holding game because both
+// players move in the same direction.
+func (g *Game) IsHoldingGame(player int8) bool {
+	if g.Variant == VariantTabula || g.IsRace() {
+		return false
+	}
+	return g.advancedAnchors(player) == 1 && len(g.Anchors(player)) <= 1 && g.PipCount(player) > g.PipCount(opponentNumber(player))
+}
+
+// IsAcePointGame returns whether the provided player is behind in the race
+// while their only anchor is on the opponent's 1 point. Tabula games never
+// contain an ace-point game because both players move in the same direction.
+func (g *Game) IsAcePointGame(player int8) bool {
+	if g.Variant == VariantTabula || g.IsRace() {
+		return false
+	}
+	anchors := g.Anchors(player)
+	return len(anchors) == 1 && anchors[0] == playerSpace(24, player, g.Variant) && g.PipCount(player) > g.PipCount(opponentNumber(player))
+}
+
+// advancedAnchors returns the number of points the provided player holds
+// among the opponent's 4 point, 5 point and bar point.
+func (g *Game) advancedAnchors(player int8) int {
+	var holding int
+	for _, point := range []int8{18, 20, 21} {
+		if PlayerCheckers(g.Board[playerSpace(point, player, g.Variant)], player) >= 2 {
+			holding++
+		}
+	}
+	return holding
+}
+
+// IsMutualHoldingGame returns whether both players hold an advanced anchor in
+// the other player's home board while neither player has a checker on the
+// bar.
+func (g *Game) IsMutualHoldingGame() bool {
+	if g.Variant == VariantTabula || g.IsRace() || g.BarPips(1) != 0 || g.BarPips(2) != 0 {
+		return false
+	}
+	return g.advancedAnchors(1) != 0 && g.advancedAnchors(2) != 0
+}
+
+// BackgameTiming returns the number of pips the provided player is behind in
+// the race, or a negative number when the player is ahead. Crunching is true
+// when the player's checkers outside of their home board, not counting the
+// checkers holding anchors, are unable to absorb an average roll, meaning the
+// player will soon be forced to break their home board.
+func (g *Game) BackgameTiming(player int8) (behind int, crunching bool) {
+	behind = g.PipCount(player) - g.PipCount(opponentNumber(player))
+	return behind, g.sparePips(player) < 8
+}
+
+// sparePips returns the number of pips the provided player's checkers outside
+// of their home board, not counting the checkers holding anchors, may move
+// before reaching the home board.
+func (g *Game) sparePips(player int8) int {
+	spare := g.BarPips(player) / 25 * 19
+	for point := int8(7); point <= 24; point++ {
+		checkers := PlayerCheckers(g.Board[playerSpace(point, player, g.Variant)], player)
+		if point >= 19 && checkers >= 2 {
+			checkers -= 2
+		}
+		spare += int(checkers) * int(point-6)
+	}
+	return spare
+}
+
+// CrunchPressure returns the number of points in the provided player's home
+// board which the player would be forced to break to play an average roll.
+// Checkers outside of the home board, other than those holding anchors, and
+// checkers in the home board which are not needed to hold a point are moved
+// first. Points are broken starting with the highest point. 0 is returned when
+// an average roll may be played without breaking a point.
+func (g *Game) CrunchPressure(player int8) int {
+	free := g.sparePips(player)
+	var points []int8
+	for point := int8(1); point <= 6; point++ {
+		checkers := PlayerCheckers(g.Board[playerSpace(point, player, g.Variant)], player)
+		if checkers >= 2 {
+			points = append(points, point)
+			checkers -= 2
+		}
+		free += int(checkers) * int(point-1)
+	}
+	var broken int
+	for i := len(points) - 1; i >= 0 && float64(free) < rollPipsMean; i-- {
+		free += 2 * int(points[i]-1)
+		broken++
+	}
+	return broken
+}
+
+// AnchorTiming returns the number of average rolls the provided player may
+// play before being forced to break the anchor held on the provided space.
+// Every other checker is moved as far as possible first, including checkers
+// holding other points. 0 is returned when the player does not hold the
+// provided space.
+func (g *Game) AnchorTiming(player int8, anchor int8) int {
+	if anchor < 1 || anchor > 24 || PlayerCheckers(g.Board[anchor], player) < 2 {
+		return 0
+	}
+	free := g.BarPips(player) / 25 * 24
+	for point := int8(2); point <= 24; point++ {
+		space := playerSpace(point, player, g.Variant)
+		checkers := PlayerCheckers(g.Board[space], player)
+		if space == anchor {
+			checkers -= 2
+		}
+		free += int(checkers) * int(point-1)
+	}
+	return int(float64(free) / rollPipsMean)
+}
+
+// HomeBoardStrength returns a score from 0 to 6 describing the strength of the
+// provided player's home board. Each point held with two or more checkers is
+// weighted by its distance from home, as higher points block more of the
+// opponent's entering numbers. A closed home board scores 6 regardless of the
+// size of the home board.
+func (g *Game) HomeBoardStrength(player int8) int {
+	homeSize := int(g.homeSize())
+	var weight int
+	for point := 1; point <= homeSize; point++ {
+		if PlayerCheckers(g.Board[playerSpace(int8(point), player, g.Variant)], player) >= 2 {
+			weight += point
+		}
+	}
+	maxWeight := homeSize * (homeSize + 1) / 2
+	return (weight*6 + maxWeight/2) / maxWeight
+}
+
+// MadePoints returns the spaces where the provided player has two or more
+// checkers, ordered from the provided player's 1 point to their 24 point.
+func (g *Game) MadePoints(player int8) []int8 {
+	var points []int8
+	for point := int8(1); point <= 24; point++ {
+		space := playerSpace(point, player, g.Variant)
+		if PlayerCheckers(g.Board[space], player) >= 2 {
+			points = append(points, space)
+		}
+	}
+	return points
+}
+
+// Gaps returns the number of points between the provided player's lowest and
+// highest made points which the player has not made. A prime has no gaps.
+func (g *Game) Gaps(player int8) int {
+	made := g.MadePoints(player)
+	if len(made) < 2 {
+		return 0
+	}
+	var low, high int8
+	for point := int8(1); point <= 24; point++ {
+		space := playerSpace(point, player, g.Variant)
+		if space == made[0] {
+			low = point
+		} else if space == made[len(made)-1] {
+			high = point
+		}
+	}
+	return int(high-low+1) - len(made)
+}
+
+// EscapeNumbers returns the number of rolls the opponent could use to move at
+// least one checker from behind the provided player's highest made point to
+// beyond it. Rolls are counted out of 36. 0 is returned in tabula games, as
+// both players move in the same direction.
+func (g *Game) EscapeNumbers(player int8) int {
+	made := g.MadePoints(player)
+	if g.Variant == VariantTabula || len(made) == 0 {
+		return 0
+	}
+	opponent := opponentNumber(player)
+	front := made[len(made)-1]
+	var high int8
+	for point := int8(1); point <= 24; point++ {
+		if playerSpace(point, player, g.Variant) == front {
+			high = point
+		}
+	}
+	homeSpace := SpaceHomePlayer
+	if opponent == 2 {
+		homeSpace = SpaceHomeOpponent
+	}
+	escaped := func(board []int8) int8 {
+		checkers := PlayerCheckers(board[homeSpace], opponent)
+		for point := high + 1; point <= 24; point++ {
+			checkers += PlayerCheckers(board[playerSpace(point, player, g.Variant)], opponent)
+		}
+		return checkers
+	}
+	before := escaped(g.Board)
+	if before == CheckerCount(g.Variant) {
+		return 0
+	}
+	var escapes int
+	eachRoll(g.Variant, func(roll1 int8, roll2 int8, roll3 int8, outcomes int) {
+		var escape bool
+		g.eachSequence(opponent, roll1, roll2, roll3, func(moves [][2]int8) {
+			if escape {
+				return
+			}
+			gc := g.Copy(true)
+			gc.Turn = opponent
+			gc.Roll1, gc.Roll2, gc.Roll3 = roll1, roll2, roll3
+			gc.Moves = nil
+			for _, move := range moves {
+				gc.addMove(move[:])
+			}
+			escape = escaped(gc.Board) > before
+		})
+		if escape {
+			escapes += outcomes
+		}
+	})
+	return escapes
+}
+
+// GamePhase is the stage of a game.
+type GamePhase int8
+
+const (
+	PhaseOpening GamePhase = 0 // Players have moved fewer than openingPips pips in total.
+	PhaseMiddle  GamePhase = 1 // The players' checkers are in contact.
+	PhaseRace    GamePhase = 2 // The players' checkers have moved past each other.
+	PhaseBearOff GamePhase = 3 // A player is bearing off and no further contact is possible.
+)
+
+// openingPips is the total number of pips both players may move before the
+// opening phase of a game ends. This is roughly two rolls each.
+const openingPips = 32
+
+// Phase returns the stage of the game. A game is in the bear-off phase once
+// there is no further contact and either player may bear off or has borne off
+// a checker. A game without contact is otherwise a race. Tabula games are
+// never considered a race because both players move in the same direction.
+func (g *Game) Phase() GamePhase {
+	if g.IsRace() {
+		for _, player := range []int8{1, 2} {
+			if g.OffCount(player) > 0 || g.MayBearOff(player, false) {
+				return PhaseBearOff
+			}
+		}
+		return PhaseRace
+	} else if g.CheckerProgress(1)+g.CheckerProgress(2) < openingPips {
+		return PhaseOpening
+	}
+	return PhaseMiddle
+}
+
+// rollOutcomes returns the number of possible outcomes of a single roll.
+func rollOutcomes(variant int8) int {
+	if variant == VariantTabula {
+		return 216
+	}
+	return 36
+}
+
+// eachRoll calls the provided function with each distinct roll and the
+// number of outcomes which produce that roll. The third die is only rolled in
+// tabula games.
+func eachRoll(variant int8, f func(roll1 int8, roll2 int8, roll3 int8, outcomes int)) {
+	for roll1 := int8(1); roll1 <= 6; roll1++ {
+		for roll2 := roll1; roll2 <= 6; roll2++ {
+			if variant != VariantTabula {
+				outcomes := 2
+				if roll1 == roll2 {
+					outcomes = 1
+				}
+				f(roll1, roll2, 0, outcomes)
+				continue
+			}
+			for roll3 := roll2; roll3 <= 6; roll3++ {
+				outcomes := 6
+				if roll1 == roll2 && roll2 == roll3 {
+					outcomes = 1
+				} else if roll1 == roll2 || roll2 == roll3 {
+					outcomes = 3
+				}
+				f(roll1, roll2, roll3, outcomes)
+			}
+		}
+	}
+}
+
+// sequences returns each legal sequence of moves the current player may play
+// using the remaining dice.
+func (g *Game) sequences() [][][2]int8 {
+	b, ok := g.TabulaBoard()
+	if !ok {
+		return nil
+	}
+	barSpace := SpaceBarPlayer
+	if g.Turn == 2 {
+		barSpace = SpaceBarOpponent
+	}
+	available, _ := b.Available(g.Turn)
+	var sequences [][][2]int8
+SEQUENCES:
+	for i := range available {
+		onBar := PlayerCheckers(g.Board[barSpace], g.Turn)
+		var moves [][2]int8
+		for _, move := range available[i] {
+			if move[0] == 0 && move[1] == 0 {
+				break
+			} else if onBar > 0 {
+				if move[0] != barSpace {
+					continue SEQUENCES
+				}
+				onBar--
+			}
+			moves = append(moves, move)
+		}
+		sequences = append(sequences, moves)
+	}
+	return sequences
+}
+
+// MoveTree returns each legal sequence of moves the current player may play
+// using the remaining dice, grouped by the first move of each sequence. Each
+// first move, formatted using FormatMoves, is mapped to the distinct moves
+// which may follow it.
+func (g *Game) MoveTree(local bool) map[string][][][]int8 {
+	tree := make(map[string][][][]int8)
+	seen := make(map[string]bool)
+	for _, sequence := range g.sequences() {
+		if len(sequence) == 0 {
+			continue
+		}
+		moves := sequenceMoves(sequence)
+		key := string(FormatMoves(moves))
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		if local {
+			moves = FlipMoves(moves, g.Turn, g.Variant)
+		}
+		first := string(FormatMoves(moves[:1]))
+		tree[first] = append(tree[first], moves[1:])
+	}
+	return tree
+}
+
+// DoubleHitMoves returns each legal sequence of moves the current player may
+// play using the remaining dice which hits two or more of the opponent's
+// checkers.
+func (g *Game) DoubleHitMoves(local bool) [][][]int8 {
+	opponent := opponentNumber(g.Turn)
+	barSpace := SpaceBarPlayer
+	if opponent == 2 {
+		barSpace = SpaceBarOpponent
+	}
+	onBar := PlayerCheckers(g.Board[barSpace], opponent)
+	var hits [][][]int8
+	seen := make(map[string]bool)
+	for _, sequence := range g.sequences() {
+		moves := sequenceMoves(sequence)
+		key := string(FormatMoves(moves))
+		if len(moves) < 2 || seen[key] {
+			continue
+		}
+		seen[key] = true
+		gc := g.Copy(true)
+		for _, move := range moves {
+			gc.addMove(move)
+		}
+		if PlayerCheckers(gc.Board[barSpace], opponent)-onBar < 2 {
+			continue
+		}
+		if local {
+			moves = FlipMoves(moves, g.Turn, g.Variant)
+		}
+		hits = append(hits, moves)
+	}
+	return hits
+}
+
+// maxMoveCount returns the greatest number of moves the current player may
+// play using the remaining dice.
+func (g *Game) maxMoveCount() int {
+	var max int
+	for _, moves := range g.sequences() {
+		if len(moves) > max {
+			max = len(moves)
+		}
+	}
+	return max
+}
+
+// eachSequence calls the provided function with each legal sequence of moves
+// the provided player could play using the provided roll. The game's current
+// dice and pending moves are ignored.
+func (g *Game) eachSequence(player int8, roll1 int8, roll2 int8, roll3 int8, f func(moves [][2]int8)) {
+	gc := g.Copy(true)
+	gc.Turn = player
+	gc.Roll1, gc.Roll2, gc.Roll3 = roll1, roll2, roll3
+	gc.Moves = nil
+	for _, moves := range gc.sequences() {
+		f(moves)
+	}
+}
+
+// hitSpaces returns the spaces containing the provided player's blots which
+// the opponent could hit using the provided roll.
+func (g *Game) hitSpaces(player int8, roll1 int8, roll2 int8, roll3 int8) map[int8]bool {
+	hit := make(map[int8]bool)
+	g.eachSequence(opponentNumber(player), roll1, roll2, roll3, func(moves [][2]int8) {
+		for _, move := range moves {
+			if move[1] >= 1 && move[1] <= 24 && PlayerCheckers(g.Board[move[1]], player) == 1 {
+				hit[move[1]] = true
+			}
+		}
+	})
+	return hit
+}
+
+// ThreatenedSpaces returns each space containing one of the provided player's
+// blots, mapped to the number of rolls the opponent could use to hit that
+// blot. Rolls are counted out of 36, or out of 216 in tabula games.
+func (g *Game) ThreatenedSpaces(player int8) map[int8]int {
+	threatened := make(map[int8]int)
+	for space := int8(1); space <= 24; space++ {
+		if PlayerCheckers(g.Board[space], player) == 1 {
+			threatened[space] = 0
+		}
+	}
+	if len(threatened) == 0 {
+		return threatened
+	}
+	eachRoll(g.Variant, func(roll1 int8, roll2 int8, roll3 int8, outcomes int) {
+		for space := range g.hitSpaces(player, roll1, roll2, roll3) {
+			threatened[space] += outcomes
+		}
+	})
+	return threatened
+}
+
+// Shots returns the number of rolls the opponent could use to hit at least one
+// of the provided player's blots. Rolls are counted out of 36, or out of 216
+// in tabula games.
+func (g *Game) Shots(player int8) int {
+	var shots int
+	eachRoll(g.Variant, func(roll1 int8, roll2 int8, roll3 int8, outcomes int) {
+		if len(g.hitSpaces(player, roll1, roll2, roll3)) != 0 {
+			shots += outcomes
+		}
+	})
+	return shots
+}
+
+// SafeFrom returns whether the opponent is unable to hit the provided player's
+// blot on the provided space using any roll. Spaces which do not contain one
+// of the player's blots are always safe.
+func (g *Game) SafeFrom(player int8, blotSpace int8) bool {
+	if blotSpace < 1 || blotSpace > 24 || PlayerCheckers(g.Board[blotSpace], player) != 1 {
+		return true
+	}
+	safe := true
+	eachRoll(g.Variant, func(roll1 int8, roll2 int8, roll3 int8, outcomes int) {
+		if safe && g.hitSpaces(player, roll1, roll2, roll3)[blotSpace] {
+			safe = false
+		}
+	})
+	return safe
+}
+
+// MinShotBlot returns the space containing the provided player's blot which
+// the opponent could hit using the fewest rolls, and the number of those
+// rolls. When several blots are equally exposed, the lowest space is returned.
+// A space of -1 is returned when the player has no blots.
+func (g *Game) MinShotBlot(player int8) (space int8, shots int) {
+	space = -1
+	threatened := g.ThreatenedSpaces(player)
+	for s := int8(1); s <= 24; s++ {
+		count, ok := threatened[s]
+		if ok && (space == -1 || count < shots) {
+			space, shots = s, count
+		}
+	}
+	return space, shots
+}
+
+// ShotsAfterMove returns the number of rolls the opponent could use to hit at
+// least one of the current player's blots after the provided moves are played.
+// Rolls which hit more than one blot are counted once. -1 is returned when
+// the moves may not be played.
+func (g *Game) ShotsAfterMove(moves [][]int8, local bool) int {
+	player := g.Turn
+	gc := g.Copy(false)
+	ok, _ := gc.AddMoves(moves, local)
+	if !ok {
+		return -1
+	}
+	return gc.Shots(player)
+}
+
+// EntryExposure returns the number of rolls the opponent could use to hit at
+// least one of the provided player's blots after the player enters a checker
+// from the bar on the provided space. When local is true, the space is
+// numbered from the perspective of the provided player. -1 is returned when the
+// player may not currently enter a checker on the provided space.
+func (g *Game) EntryExposure(player int8, entrySpace int8, local bool) int {
+	if player != g.Turn {
+		return -1
+	}
+	barSpace := SpaceBarPlayer
+	if player == 2 {
+		barSpace = SpaceBarOpponent
+	}
+	if local {
+		entrySpace = FlipSpace(entrySpace, player, g.Variant)
+	}
+	return g.ShotsAfterMove([][]int8{{barSpace, entrySpace}}, false)
+}
@@ -0,0 +1,233 @@
+package bgammon
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIsMutualHoldingGame(t *testing.T) {
+	testCases := []struct {
+		name     string
+		board    map[int8]int8
+		expected bool
+	}{
+		{
+			name:     "starting position",
+			board:    map[int8]int8{24: 2, 13: 5, 8: 3, 6: 5, 1: -2, 12: -5, 17: -3, 19: -5},
+			expected: false,
+		},
+		{
+			name:     "both players hold an anchor",
+			board:    map[int8]int8{20: 2, 13: 5, 8: 3, 6: 5, 5: -2, 12: -5, 17: -3, 19: -5},
+			expected: true,
+		},
+		{
+			name:     "only one player holds an anchor",
+			board:    map[int8]int8{20: 2, 13: 5, 8: 3, 6: 5, 1: -2, 12: -5, 17: -3, 19: -5},
+			expected: false,
+		},
+		{
+			name:     "checker on the bar",
+			board:    map[int8]int8{20: 2, 13: 4, SpaceBarPlayer: 1, 8: 3, 6: 5, 5: -2, 12: -5, 17: -3, 19: -5},
+			expected: false,
+		},
+	}
+	for _, tc := range testCases {
+		board := make([]int8, BoardSpaces)
+		for space, checkers := range tc.board {
+			board[space] = checkers
+		}
+		g := newTestGame(VariantBackgammon, board, 1, 0, 0)
+		if holding := g.IsMutualHoldingGame(); holding != tc.expected {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.expected, holding)
+		}
+		if holding := newTestGame(VariantBackgammon, MirrorBoard(board, VariantBackgammon), 2, 0, 0).IsMutualHoldingGame(); holding != tc.expected {
+			t.Errorf("%s: mirrored: expected %v, got %v", tc.name, tc.expected, holding)
+		}
+	}
+}
+
+func TestMadePoints(t *testing.T) {
+	testCases := []struct {
+		name  string
+		board map[int8]int8
+		made  []int8
+		gaps  int
+	}{
+		{
+			name:  "no made points",
+			board: map[int8]int8{6: 1, 8: 1},
+			made:  nil,
+			gaps:  0,
+		},
+		{
+			name:  "one made point",
+			board: map[int8]int8{6: 3, 8: 1},
+			made:  []int8{6},
+			gaps:  0,
+		},
+		{
+			name:  "prime",
+			board: map[int8]int8{4: 2, 5: 2, 6: 3, 7: 2},
+			made:  []int8{4, 5, 6, 7},
+			gaps:  0,
+		},
+		{
+			name:  "gaps",
+			board: map[int8]int8{2: 2, 4: 2, 5: 1, 6: 3, 8: 2},
+			made:  []int8{2, 4, 6, 8},
+			gaps:  3,
+		},
+	}
+	for _, tc := range testCases {
+		for _, player := range []int8{1, 2} {
+			// The opponent's checkers on the 24 point never count towards made points.
+			board := playerBoard(player, VariantBackgammon, tc.board)
+			g := newTestGame(VariantBackgammon, board, player, 0, 0)
+			made := g.MadePoints(player)
+			var expected []int8
+			for _, point := range tc.made {
+				expected = append(expected, playerSpace(point, player, VariantBackgammon))
+			}
+			if !reflect.DeepEqual(made, expected) {
+				t.Errorf("%s: player %d: expected made points %v, got %v", tc.name, player, expected, made)
+			}
+			if gaps := g.Gaps(player); gaps != tc.gaps {
+				t.Errorf("%s: player %d: expected %d gaps, got %d", tc.name, player, tc.gaps, gaps)
+			}
+		}
+	}
+}
+
+func TestHomeBoardStrength(t *testing.T) {
+	testCases := []struct {
+		name     string
+		homeSize int8
+		board    map[int8]int8
+		expected int
+	}{
+		{
+			name:     "empty home board",
+			homeSize: DefaultHomeSize,
+			board:    map[int8]int8{8: 15},
+			expected: 0,
+		},
+		{
+			name:     "full home board",
+			homeSize: DefaultHomeSize,
+			board:    map[int8]int8{1: 2, 2: 2, 3: 2, 4: 2, 5: 2, 6: 2, 8: 3},
+			expected: 6,
+		},
+		{
+			name:     "broken home board",
+			homeSize: DefaultHomeSize,
+			board:    map[int8]int8{1: 2, 2: 2, 3: 2, 4: 1, 5: 2, 6: 2, 8: 4},
+			expected: 5,
+		},
+		{
+			name:     "full small home board",
+			homeSize: 4,
+			board:    map[int8]int8{1: 2, 2: 2, 3: 2, 4: 2, 8: 7},
+			expected: 6,
+		},
+		{
+			name:     "broken small home board",
+			homeSize: 4,
+			board:    map[int8]int8{1: 2, 2: 2, 3: 2, 4: 1, 5: 2, 8: 6},
+			expected: 4,
+		},
+	}
+	for _, tc := range testCases {
+		for _, player := range []int8{1, 2} {
+			board := playerBoard(player, VariantBackgammon, tc.board)
+			g := newTestGame(VariantBackgammon, board, player, 0, 0)
+			g.HomeSize = tc.homeSize
+			if strength := g.HomeBoardStrength(player); strength != tc.expected {
+				t.Errorf("%s: player %d: expected strength %d, got %d", tc.name, player, tc.expected, strength)
+			}
+		}
+	}
+}
+
+func TestEntryExposure(t *testing.T) {
+	board := make([]int8, BoardSpaces)
+	board[SpaceBarPlayer], board[13], board[8] = 1, 7, 7
+	board[SpaceBarOpponent], board[12], board[17] = -1, -7, -7
+
+	for _, player := range []int8{1, 2} {
+		g := newTestGame(VariantBackgammon, board, player, 3, 1)
+		absolute := g.EntryExposure(player, EntrySpace(player, 3, VariantBackgammon), false)
+		if absolute <= 0 {
+			t.Fatalf("player %d: expected the entered checker to be exposed, got %d", player, absolute)
+		}
+		if local := g.EntryExposure(player, 22, true); local != absolute {
+			t.Errorf("player %d: expected local exposure %d to match absolute exposure %d", player, local, absolute)
+		}
+		if blocked := g.EntryExposure(player, EntrySpace(player, 6, VariantBackgammon), false); blocked != -1 {
+			t.Errorf("player %d: expected -1 when entering using a die which was not rolled, got %d", player, blocked)
+		}
+	}
+}
+
+func TestPhase(t *testing.T) {
+	testCases := []struct {
+		name     string
+		variant  int8
+		board    map[int8]int8
+		expected GamePhase
+	}{
+		{
+			name:     "starting position",
+			variant:  VariantBackgammon,
+			board:    map[int8]int8{24: 2, 13: 5, 8: 3, 6: 5, 1: -2, 12: -5, 17: -3, 19: -5},
+			expected: PhaseOpening,
+		},
+		{
+			name:     "opening move played",
+			variant:  VariantBackgammon,
+			board:    map[int8]int8{24: 2, 13: 5, 8: 2, 6: 4, 5: 2, 1: -2, 12: -5, 17: -3, 19: -5},
+			expected: PhaseOpening,
+		},
+		{
+			name:     "contact after the opening",
+			variant:  VariantBackgammon,
+			board:    map[int8]int8{13: 2, 8: 4, 6: 5, 5: 2, 4: 2, 1: -2, 12: -5, 17: -3, 19: -5},
+			expected: PhaseMiddle,
+		},
+		{
+			name:     "no contact",
+			variant:  VariantBackgammon,
+			board:    map[int8]int8{8: 5, 7: 5, 6: 5, 17: -5, 18: -5, 19: -5},
+			expected: PhaseRace,
+		},
+		{
+			name:     "bearing off",
+			variant:  VariantBackgammon,
+			board:    map[int8]int8{6: 5, 5: 5, 4: 5, 17: -5, 18: -5, 19: -5},
+			expected: PhaseBearOff,
+		},
+		{
+			name:     "checker borne off",
+			variant:  VariantBackgammon,
+			board:    map[int8]int8{8: 1, 6: 5, 5: 5, 4: 3, SpaceHomePlayer: 1, 17: -5, 18: -5, 19: -5},
+			expected: PhaseBearOff,
+		},
+		{
+			name:     "tabula without contact",
+			variant:  VariantTabula,
+			board:    map[int8]int8{6: 5, 5: 5, 4: 5, 17: -5, 18: -5, 19: -5},
+			expected: PhaseMiddle,
+		},
+	}
+	for _, tc := range testCases {
+		board := make([]int8, BoardSpaces)
+		for space, checkers := range tc.board {
+			board[space] = checkers
+		}
+		g := newTestGame(tc.variant, board, 1, 0, 0)
+		g.Player1.Entered, g.Player2.Entered = true, true
+		if phase := g.Phase(); phase != tc.expected {
+			t.Errorf("%s: expected phase %d, got %d", tc.name, tc.expected, phase)
+		}
+	}
+}
@@ -0,0 +1,279 @@
+package bgammon
+
+import (
+	"math"
+	"sync"
+)
+
+// bearOffPosition is the distribution of a player's checkers within their home
+// board, from the perspective of that player. Index 0 is the 1 point.
+type bearOffPosition [6]int8
+
+func (p bearOffPosition) key() uint32 {
+	var k uint32
+	for i := range p {
+		k |= uint32(p[i]) << (4 * i)
+	}
+	return k
+}
+
+// The bear-off table holds the expected number of rolls needed to bear off
+// each position. It is populated lazily as positions are evaluated.
+var (
+	bearOffTable = make(map[uint32]float64)
+	bearOffLock  = &sync.Mutex{}
+)
+
+// bearOffPlays returns each position reachable by playing a single die.
+func bearOffPlays(p bearOffPosition, die int8) []bearOffPosition {
+	highest := int8(-1)
+	for i := int8(5); i >= 0; i-- {
+		if p[i] != 0 {
+			highest = i
+			break
+		}
+	}
+	var plays []bearOffPosition
+	for i := int8(0); i <= highest; i++ {
+		if p[i] == 0 {
+			continue
+		}
+		to := i + 1 - die
+		if to < 0 && i != highest {
+			continue
+		}
+		play := p
+		play[i]--
+		if to > 0 {
+			play[to-1]++
+		}
+		plays = append(plays, play)
+	}
+	return plays
+}
+
+// bearOffResults returns each distinct position reachable by playing the
+// provided dice in order.
+func bearOffResults(p bearOffPosition, dice []int8) []bearOffPosition {
+	positions := []bearOffPosition{p}
+	for _, die := range dice {
+		seen := make(map[bearOffPosition]bool)
+		var next []bearOffPosition
+		for _, position := range positions {
+			plays := bearOffPlays(position, die)
+			if len(plays) == 0 {
+				plays = []bearOffPosition{position}
+			}
+			for _, play := range plays {
+				if !seen[play] {
+					seen[play] = true
+					next = append(next, play)
+				}
+			}
+		}
+		positions = next
+	}
+	return positions
+}
+
+// bearOffBest returns the position reachable using the provided roll which
+// requires the fewest rolls to bear off, along with that number of rolls.
+// The bear-off lock must be held.
+func bearOffBest(p bearOffPosition, roll1 int8, roll2 int8) (bearOffPosition, float64) {
+	dice := [][]int8{{roll1, roll2}, {roll2, roll1}}
+	if roll1 == roll2 {
+		dice = [][]int8{{roll1, roll1, roll1, roll1}}
+	}
+	best, bestRolls := p, math.Inf(1)
+	for i := range dice {
+		for _, result := range bearOffResults(p, dice[i]) {
+			rolls := bearOffExpected(result)
+			if rolls < bestRolls {
+				best, bestRolls = result, rolls
+			}
+		}
+	}
+	return best, bestRolls
+}
+
+// bearOffExpected returns the expected number of rolls needed to bear off all
+// checkers in the provided position. The bear-off lock must be held.
+func bearOffExpected(p bearOffPosition) float64 {
+	if p == (bearOffPosition{}) {
+		return 0
+	}
+	k := p.key()
+	if rolls, ok := bearOffTable[k]; ok {
+		return rolls
+	}
+	var total float64
+	for roll1 := int8(1); roll1 <= 6; roll1++ {
+		for roll2 := roll1; roll2 <= 6; roll2++ {
+			weight := 2.0
+			if roll1 == roll2 {
+				weight = 1
+			}
+			_, rolls := bearOffBest(p, roll1, roll2)
+			total += weight * rolls
+		}
+	}
+	rolls := 1 + total/36
+	bearOffTable[k] = rolls
+	return rolls
+}
+
+// homeCheckers returns the distribution of the provided player's checkers
+// within their home board.
+func (g *Game) homeCheckers(player int8) bearOffPosition {
+	var p bearOffPosition
+	for point := int8(1); point <= 6; point++ {
+		p[point-1] = PlayerCheckers(g.Board[playerSpace(point, player, g.Variant)], player)
+	}
+	return p
+}
+
+// ExpectedRollsToFinish returns the expected number of rolls the provided
+// player needs to bear off all of their checkers. -1 is returned when the
+// player is not bearing off in a backgammon game without contact.
+func (g *Game) ExpectedRollsToFinish(player int8) float64 {
+	if g.Variant != VariantBackgammon || !g.IsRace() || !g.MayBearOff(player, false) {
+		return -1
+	}
+	bearOffLock.Lock()
+	defer bearOffLock.Unlock()
+	return bearOffExpected(g.homeCheckers(player))
+}
+
+// MinRollsToWin returns the fewest number of rolls the provided player needs
+// to bear off all of their checkers, ignoring the opponent. This assumes every
+// roll is a doublet, so four checkers are borne off each roll. -1 is returned
+// when the player is not bearing off in a backgammon game.
+func (g *Game) MinRollsToWin(player int8) int {
+	if g.Variant != VariantBackgammon || !g.MayBearOff(player, false) {
+		return -1
+	}
+	var checkers int
+	for _, c := range g.homeCheckers(player) {
+		checkers += int(c)
+	}
+	return (checkers + 3) / 4
+}
+
+// WastageTrap returns whether the provided player is bearing off with three or
+// more checkers stacked on a point above at least two empty points within
+// their home board. Higher rolls will be wasted on the stacked point while the
+// lower points remain empty. Tabula games are not considered, as checkers are
+// borne off from the second half of the board.
+func (g *Game) WastageTrap(player int8) bool {
+	if g.Variant == VariantTabula || !g.MayBearOff(player, false) {
+		return false
+	}
+	home := g.homeCheckers(player)
+	var empty int
+	for point := range home {
+		if home[point] == 0 {
+			empty++
+		} else if home[point] >= 3 && empty >= 2 {
+			return true
+		}
+	}
+	return false
+}
+
+// EffectivePipCount returns the expected number of rolls the provided player
+// needs to bear off multiplied by the average number of pips in a roll. This
+// accounts for pips wasted by the distribution of checkers in the home board.
+// The raw pip count is returned when the player is not bearing off.
+func (g *Game) EffectivePipCount(player int8) float64 {
+	rolls := g.ExpectedRollsToFinish(player)
+	if rolls < 0 {
+		return float64(g.PipCount(player))
+	}
+	return rolls * rollPipsMean
+}
+
+// ExpectedWastage returns the number of pips the provided player is expected
+// to waste while bearing off, calculated as the difference between the
+// player's effective pip count and their pip count. 0 is returned when the
+// player is not bearing off.
+func (g *Game) ExpectedWastage(player int8) float64 {
+	if g.ExpectedRollsToFinish(player) < 0 {
+		return 0
+	}
+	return g.EffectivePipCount(player) - float64(g.PipCount(player))
+}
+
+// EfficientBearOff returns the legal sequence of moves using the remaining
+// dice which moves the current player's checkers the greatest number of pips,
+// wasting as little of the roll as possible. Nil is returned when the current
+// player is not bearing off.
+func (g *Game) EfficientBearOff(local bool) [][]int8 {
+	if g.Turn == 0 || g.Roll1 == 0 || !g.MayBearOff(g.Turn, false) {
+		return nil
+	}
+	moves := g.bestSequence(func(gc *Game) float64 {
+		return -float64(gc.PipCount(g.Turn))
+	})
+	if local {
+		moves = FlipMoves(moves, g.Turn, g.Variant)
+	}
+	return moves
+}
+
+// BearOffWaste returns the number of pips the pending moves waste compared with
+// the most efficient bear-off using the same roll. The remaining dice are
+// assumed to be played efficiently. 0 is returned when the pending moves are
+// efficient or when the current player is not bearing off. The result is the
+// same from either perspective, so local is ignored.
+func (g *Game) BearOffWaste(local bool) int {
+	if len(g.Moves) == 0 || len(g.boardStates) == 0 {
+		return 0
+	}
+	start := g.Copy(true)
+	copy(start.Board, g.boardStates[0])
+	start.Player1.Entered, start.Player2.Entered = g.enteredStates[0][0], g.enteredStates[0][1]
+	start.Moves = nil
+	best := start.EfficientBearOff(false)
+	if best == nil {
+		return 0
+	}
+	for _, move := range best {
+		start.addMove(move)
+	}
+	current := g.Copy(true)
+	for _, move := range current.EfficientBearOff(false) {
+		current.addMove(move)
+	}
+	waste := current.PipCount(g.Turn) - start.PipCount(g.Turn)
+	if waste < 0 {
+		return 0
+	}
+	return waste
+}
+
+// WastedDice returns each die value mapped to the number of pips it would
+// waste if used to bear off one of the provided player's checkers. A die
+// wastes pips when it is greater than the highest point holding one of the
+// player's checkers. Nil is returned when the player may not bear off.
+func (g *Game) WastedDice(player int8) map[int8]int8 {
+	if !g.MayBearOff(player, false) {
+		return nil
+	}
+	p := g.homeCheckers(player)
+	var highest int8
+	for point := int8(6); point >= 1; point-- {
+		if p[point-1] != 0 {
+			highest = point
+			break
+		}
+	}
+	wasted := make(map[int8]int8)
+	for die := int8(1); die <= 6; die++ {
+		if highest != 0 && die > highest {
+			wasted[die] = die - highest
+		} else {
+			wasted[die] = 0
+		}
+	}
+	return wasted
+}
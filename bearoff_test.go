@@ -0,0 +1,89 @@
+package bgammon
+
+import (
+	"math"
+	"testing"
+)
+
+func TestExpectedRollsToFinish(t *testing.T) {
+	testCases := []struct {
+		name     string
+		board    map[int8]int8
+		expected float64
+	}{
+		{name: "one checker", board: map[int8]int8{1: 1}, expected: 1},
+		{name: "two checkers", board: map[int8]int8{1: 2}, expected: 1},
+		// Three checkers are borne off by doublets, otherwise one checker remains.
+		{name: "three checkers", board: map[int8]int8{1: 3}, expected: 1 + 30.0/36},
+		// Doublets leave one checker, otherwise three checkers remain.
+		{name: "five checkers", board: map[int8]int8{1: 5}, expected: 1 + 30.0/36*(1+30.0/36) + 6.0/36},
+	}
+	for _, tc := range testCases {
+		for _, player := range []int8{1, 2} {
+			board := playerBoard(player, VariantBackgammon, tc.board)
+			g := newTestGame(VariantBackgammon, board, player, 0, 0)
+			if rolls := g.ExpectedRollsToFinish(player); math.Abs(rolls-tc.expected) > 0.0001 {
+				t.Errorf("%s: player %d: expected %f rolls, got %f", tc.name, player, tc.expected, rolls)
+			}
+		}
+	}
+
+	g := NewGame(VariantBackgammon)
+	if rolls := g.ExpectedRollsToFinish(1); rolls != -1 {
+		t.Errorf("expected -1 when the players are in contact, got %f", rolls)
+	}
+
+	board := make([]int8, BoardSpaces)
+	board[6], board[1] = 1, 1
+	board[SpaceHomePlayer] = 13
+	board[SpaceHomeOpponent] = -15
+	g = newTestGame(VariantBackgammon, board, 1, 0, 0)
+	six := g.ExpectedRollsToFinish(1)
+	g.Board[6], g.Board[5] = 0, 1
+	if five := g.ExpectedRollsToFinish(1); five > six {
+		t.Errorf("expected fewer rolls with a checker on the 5 point than on the 6 point, got %f and %f", five, six)
+	}
+}
+
+func TestExpectedWastage(t *testing.T) {
+	// Both distributions have a pip count of 21.
+	smooth := map[int8]int8{1: 1, 2: 1, 3: 1, 4: 1, 5: 1, 6: 1}
+	stacked := map[int8]int8{1: 9, 6: 2}
+
+	wastage := func(player int8, points map[int8]int8) float64 {
+		board := playerBoard(player, VariantBackgammon, points)
+		return newTestGame(VariantBackgammon, board, player, 0, 0).ExpectedWastage(player)
+	}
+	for _, player := range []int8{1, 2} {
+		low, high := wastage(player, smooth), wastage(player, stacked)
+		if low <= 0 {
+			t.Errorf("player %d: expected the smooth distribution to waste pips, got %f", player, low)
+		}
+		if high <= low {
+			t.Errorf("player %d: expected the stacked distribution to waste more than %f pips, got %f", player, low, high)
+		}
+	}
+
+	if waste := NewGame(VariantBackgammon).ExpectedWastage(1); waste != 0 {
+		t.Errorf("expected no wastage when the players are in contact, got %f", waste)
+	}
+}
+
+func TestEfficientBearOffLocal(t *testing.T) {
+	board := make([]int8, BoardSpaces)
+	board[22], board[SpaceHomeOpponent] = -1, -14
+	board[1] = 15
+
+	g := newTestGame(VariantBackgammon, board, 2, 3, 3)
+	absolute := g.EfficientBearOff(false)
+	if len(absolute) != 1 || absolute[0][0] != 22 || absolute[0][1] != SpaceHomeOpponent {
+		t.Fatalf("expected 22/off, got %s", FormatMoves(absolute))
+	}
+	local := g.EfficientBearOff(true)
+	if len(local) != 1 || local[0][0] != 3 || local[0][1] != SpaceHomePlayer {
+		t.Fatalf("expected 3/off from the perspective of player 2, got %s", FormatMoves(local))
+	}
+	if waste := g.BearOffWaste(true); waste != g.BearOffWaste(false) {
+		t.Fatalf("expected the same waste from either perspective, got %d and %d", waste, g.BearOffWaste(false))
+	}
+}
@@ -17,6 +17,9 @@ const (
 // BoardSpaces is the total number of spaces needed to represent a backgammon board.
 const BoardSpaces = 28
 
+// DefaultHomeSize is the number of points in each player's home board.
+const DefaultHomeSize = 6
+
 // NewBoard returns a new backgammon board represented as integers. Positive
 // integers represent player 1's checkers and negative integers represent
 // player 2's checkers. The board's space numbering is always from the
@@ -38,6 +41,31 @@ func NewBoard(variant int8) []int8 {
 	return space
 }
 
+// MirrorBoard returns the provided board as it would appear if the players
+// swapped roles. Each player's checkers become the other player's checkers,
+// and spaces are numbered from the perspective of the other player.
+func MirrorBoard(board []int8, variant int8) []int8 {
+	mirror := make([]int8, BoardSpaces)
+	for space := int8(1); space <= 24; space++ {
+		mirror[space] = board[FlipSpace(space, 2, variant)] * -1
+	}
+	mirror[SpaceHomePlayer], mirror[SpaceHomeOpponent] = board[SpaceHomeOpponent]*-1, board[SpaceHomePlayer]*-1
+	mirror[SpaceBarPlayer], mirror[SpaceBarOpponent] = board[SpaceBarOpponent]*-1, board[SpaceBarPlayer]*-1
+	return mirror
+}
+
+// CheckerCount returns the number of checkers each player starts with in the
+// provided variant.
+func CheckerCount(variant int8) int8 {
+	var count int8
+	for _, checkers := range NewBoard(variant) {
+		if checkers > 0 {
+			count += checkers
+		}
+	}
+	return count
+}
+
 // HomeRange returns the start and end space of the provided player's home board.
 func HomeRange(player int8, variant int8) (from int8, to int8) {
 	if player == 2 || variant == VariantTabula {
@@ -46,6 +74,25 @@ func HomeRange(player int8, variant int8) (from int8, to int8) {
 	return 1, 6
 }
 
+// EntrySpace returns the space where the provided player enters a checker from
+// the bar using the provided roll.
+func EntrySpace(player int8, roll int8, variant int8) int8 {
+	if player == 1 && variant != VariantTabula {
+		return 25 - roll
+	}
+	return roll
+}
+
+// playerSpace returns the board space of the provided point, numbered from the
+// perspective of the provided player where point 1 is the last point before
+// bearing off.
+func playerSpace(point int8, player int8, variant int8) int8 {
+	if player == 2 || variant == VariantTabula {
+		return 25 - point
+	}
+	return point
+}
+
 // RollForMove returns the roll needed to move a checker from the provided spaces.
 func RollForMove(from int8, to int8, player int8, variant int8) int8 {
 	if !ValidSpace(from) || !ValidSpace(to) {
@@ -7,6 +7,9 @@ import (
 	"net/http"
 	_ "net/http/pprof"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"code.rocket9labs.com/tslocum/bgammon/pkg/server"
 	"golang.org/x/text/language"
@@ -26,6 +29,12 @@ func main() {
 		debug          int
 		debugCommands  bool
 		rollStatistics bool
+		rematchExpiry  time.Duration
+		noUndo         bool
+		keyframes      int
+		optionalDice   bool
+		matchLengths   string
+		tersePass      bool
 	)
 	flag.StringVar(&tcpAddress, "tcp", "localhost:1337", "TCP listen address")
 	flag.StringVar(&wsAddress, "ws", "localhost:1338", "WebSocket listen address")
@@ -36,6 +45,12 @@ func main() {
 	flag.IntVar(&debug, "debug", 0, "print debug information and serve pprof on specified port")
 	flag.BoolVar(&debugCommands, "debug-commands", false, "allow players to use restricted commands")
 	flag.BoolVar(&rollStatistics, "statistics", false, "print dice roll statistics and exit")
+	flag.BoolVar(&noUndo, "no-undo", false, "do not allow players to undo moves before ending their turn")
+	flag.BoolVar(&optionalDice, "optional-dice", false, "allow players to end their turn without using as many dice as possible")
+	flag.StringVar(&matchLengths, "match-lengths", "", "comma-separated number of points players may choose to play to when creating a match (e.g. 1,3,5,7,9,11)")
+	flag.BoolVar(&tersePass, "terse-pass", false, "do not explain why turns passed automatically could not be played")
+	flag.IntVar(&keyframes, "keyframes", 0, "number of turns between board keyframes recorded in replays (0 to disable)")
+	flag.DurationVar(&rematchExpiry, "rematch-expiry", 10*time.Minute, "duration rematch tokens remain valid")
 	flag.Parse()
 
 	if dataSource == "" {
@@ -65,6 +80,22 @@ func main() {
 	}
 
 	s := server.NewServer(tz, dataSource, mailServer, passwordSalt, resetSalt, false, verbose || debug > 0, debugCommands)
+	s.SetRematchTokenExpiry(rematchExpiry)
+	s.SetNoUndo(noUndo)
+	s.SetAllowUnusedDice(optionalDice)
+	s.SetReplayKeyframeInterval(keyframes)
+	s.SetDetailedPassNotices(!tersePass)
+	if matchLengths != "" {
+		var lengths []int
+		for _, v := range strings.Split(matchLengths, ",") {
+			length, err := strconv.Atoi(strings.TrimSpace(v))
+			if err != nil || length < 1 || length > 99 {
+				log.Fatalf("Error: Invalid match length: %s", v)
+			}
+			lengths = append(lengths, length)
+		}
+		s.SetMatchLengths(lengths)
+	}
 	if tcpAddress != "" {
 		s.Listen("tcp", tcpAddress)
 	}
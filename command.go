@@ -26,12 +26,21 @@ const (
 	CommandReset         = "reset"         // Reset checker movement.
 	CommandOk            = "ok"            // Confirm checker movement and pass turn to next player.
 	CommandRematch       = "rematch"       // Confirm checker movement and pass turn to next player.
+	CommandRematchToken  = "rematchtoken"  // Generate a one-time rematch token.
+	CommandDraw          = "draw"          // Offer (or accept) a draw.
+	CommandTakeback      = "takeback"      // Request (or agree) to take back the previous turn.
+	CommandUndoDecline   = "undodecline"   // Request (or agree) to undo declining a double offer.
+	CommandPause         = "pause"         // Request (or agree) to pause the match.
+	CommandResume        = "resume"        // Request (or agree) to resume the match.
 	CommandBoard         = "board"         // Print current board state in human-readable form.
 	CommandPong          = "pong"          // Response to server ping.
 	CommandDisconnect    = "disconnect"    // Disconnect from server.
 	CommandMOTD          = "motd"          // Read (or write) the message of the day.
 	CommandBroadcast     = "broadcast"     // Send a message to all players.
 	CommandShutdown      = "shutdown"      // Prevent the creation of new matches.
+	CommandDump          = "dump"          // Serialize the state of a match.
+	CommandRestore       = "restore"       // Restore a serialized match for inspection.
+	CommandDemo          = "demo"          // Start a match between two bots.
 )
 
 type EventType string
@@ -57,6 +66,10 @@ const (
 	EventTypeSettings    = "settings"
 	EventTypeReplay      = "replay"
 	EventTypeHistory     = "history"
+	EventTypeDrawOffer   = "drawoffer"
+	EventTypeDraw        = "draw"
+	EventTypePaused      = "paused"
+	EventTypeResumed     = "resumed"
 )
 
 var HelpText = map[string]string{
@@ -64,14 +77,14 @@ var HelpText = map[string]string{
 	CommandRegister:      "<email> <username> <password> - Register an account. A valid email address must be provided.",
 	CommandResetPassword: "<email> - Request a password reset link via email.",
 	CommandPassword:      "<old> <new> - Change account password.",
-	CommandSet:           "<name> <value> - Change account setting. Available settings: highlight, pips and moves.",
+	CommandSet:           "<name> <value> - Change account setting. Available settings: highlight, pips, moves and color.",
 	CommandReplay:        "<id> - Retrieve replay of the specified game.",
 	CommandHistory:       "<username> [page] - Retrieve match history of the specified player.",
 	CommandHelp:          "[command] - Request help for all commands, or optionally a specific command.",
 	CommandSay:           "<message> - Send a chat message. This command can only be used after creating or joining a match.",
 	CommandList:          "- List all matches.",
-	CommandCreate:        "<public>/<private [password]> <points> <variant> [name] - Create a match. A variant value of 0 represents a standard game, a value of 1 represents an acey-deucey game and a value of 2 represents a tabula game.",
-	CommandJoin:          "<id>/<username> [password] - Join match by match ID or by player.",
+	CommandCreate:        "<public>/<private [password]> <points> <variant> [opening=X-Y] [autostart] [nocube] [name] - Create a match. A variant value of 0 represents a standard game, a value of 1 represents an acey-deucey game and a value of 2 represents a tabula game. The opening roll may be predetermined, and may be rolled automatically once both players have joined. The doubling cube may be disabled.",
+	CommandJoin:          "<id>/<username>/<token> [password] - Join match by match ID, by player or by rematch token.",
 	CommandLeave:         "- Leave match.",
 	CommandDouble:        "- Offer double to opponent.",
 	CommandResign:        "- Resign game. Resigning when a double is offered will decline the offer.",
@@ -80,10 +93,19 @@ var HelpText = map[string]string{
 	CommandReset:         "- Reset pending checker movement.",
 	CommandOk:            "[1-6] - Accept double offer or confirm checker movement. The parameter for this command only applies in acey-deucey games.",
 	CommandRematch:       "- Request (or accept) a rematch after a match has been finished.",
+	CommandDraw:          "- Offer (or accept) a draw. When both players agree, the current game is abandoned without changing the score. Draws are not available in rated matches.",
+	CommandTakeback:      "- Request (or agree) to take back the previous turn. When the opponent agrees, the game is restored to the start of the previous turn. Take-backs are not available in rated matches.",
+	CommandUndoDecline:   "- Request (or agree) to undo declining a double offer before the next game begins. When the opponent agrees, the double offer is restored. Not available in rated matches.",
+	CommandPause:         "- Request (or agree) to pause the match. While the match is paused, the turn clock is stopped and the dice may not be rolled and checkers may not be moved.",
+	CommandResume:        "- Request (or agree) to resume a paused match.",
+	CommandRematchToken:  "- Generate a one-time token which you and your opponent may use to rematch by joining with the token instead of a match ID.",
 	CommandBoard:         "- Request current match state.",
 	CommandPong:          "<message> - Sent in response to server ping event to prevent the connection from timing out.",
 	CommandDisconnect:    "- Disconnect from the server.",
 	CommandMOTD:          "[message] - View (or set) message of the day. Specifying a new message of the day is only available to server administrators.",
 	CommandBroadcast:     "<message> - Send a message to all players. This command is only available to server administrators.",
 	CommandShutdown:      "<minutes> <reason> - Prevent the creation of new matches and periodically warn players about the server shutting down. This command is only available to server administrators.",
+	CommandDump:          "[id] - Serialize the state of the specified match, or the current match, as JSON. This command is only available to server administrators.",
+	CommandRestore:       "<json> - Restore a match serialized using the dump command and spectate it. Restored matches may not be played. This command is only available to server administrators.",
+	CommandDemo:          "[level1] [level2] - Start a match between two bots of the specified levels (0 - random, 1 - heuristic, 2 - heuristic with optimal bear-off) and spectate it. The match ends when no spectators remain. This command is only available to server administrators.",
 }
@@ -0,0 +1,69 @@
+package bgammon
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrNotYourTurn      = errors.New("it is not your turn")
+	ErrMustEnterFromBar = errors.New("checkers on the bar must be entered first")
+	ErrCannotBearOff    = errors.New("checkers may not be borne off yet")
+	ErrNoSuchDie        = errors.New("no remaining die matches the move")
+	ErrGameOver         = errors.New("the game is over")
+	ErrMustUseBothDice  = errors.New("all dice must be used when possible")
+	ErrIllegalMove      = errors.New("illegal move")
+)
+
+// ValidateMoves returns an error describing why the provided moves are not a
+// legal way for the provided player to complete their turn, or nil when they
+// are. Moves which have already been added to the game are taken into account.
+// The returned error wraps one of the sentinel errors defined in this package.
+func (g *Game) ValidateMoves(player int8, moves [][]int8, local bool) error {
+	switch {
+	case g.Winner != 0:
+		return ErrGameOver
+	case g.Turn != player:
+		return ErrNotYourTurn
+	case g.Roll1 == 0:
+		return fmt.Errorf("dice have not been rolled: %w", ErrNoSuchDie)
+	}
+
+	barSpace := SpaceBarPlayer
+	if player == 2 {
+		barSpace = SpaceBarOpponent
+	}
+
+	gc := g.Copy(false)
+	for _, move := range moves {
+		var err error
+		from, to := move[0], move[1]
+		bearOff := to == SpaceHomePlayer || to == SpaceHomeOpponent
+		switch {
+		case !ValidSpace(from) || !ValidSpace(to):
+			err = ErrIllegalMove
+		case PlayerCheckers(gc.Board[barSpace], player) != 0 && from != barSpace:
+			err = ErrMustEnterFromBar
+		case bearOff && !gc.MayBearOff(player, local):
+			err = ErrCannotBearOff
+		case !bearOff && gc.HaveDiceRoll(from, to) == 0:
+			// Moves which use more than one die are expanded by AddMoves.
+			if _, ok := gc.ExpandMove(move, from, nil, local); !ok {
+				err = ErrNoSuchDie
+				break
+			}
+			fallthrough
+		default:
+			if ok, _ := gc.AddMoves([][]int8{move}, local); !ok {
+				err = ErrIllegalMove
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", FormatMoves([][]int8{move}), err)
+		}
+	}
+	if gc.Winner == 0 && !gc.AllowUnusedDice && len(gc.LegalMoves(local)) != 0 {
+		return ErrMustUseBothDice
+	}
+	return nil
+}
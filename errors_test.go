@@ -0,0 +1,26 @@
+package bgammon
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateMoves(t *testing.T) {
+	testCases := []struct {
+		name     string
+		moves    [][]int8
+		expected error
+	}{
+		{name: "single dice", moves: [][]int8{{24, 18}, {13, 8}}, expected: nil},
+		{name: "combined dice", moves: [][]int8{{24, 13}}, expected: nil},
+		{name: "no such die", moves: [][]int8{{24, 20}}, expected: ErrNoSuchDie},
+		{name: "no such combination", moves: [][]int8{{24, 12}}, expected: ErrNoSuchDie},
+		{name: "unused die", moves: [][]int8{{24, 18}}, expected: ErrMustUseBothDice},
+	}
+	for _, tc := range testCases {
+		g := newTestGame(VariantBackgammon, NewBoard(VariantBackgammon), 1, 6, 5)
+		if err := g.ValidateMoves(1, tc.moves, false); !errors.Is(err, tc.expected) {
+			t.Errorf("%s: expected error %v, got %v", tc.name, tc.expected, err)
+		}
+	}
+}
@@ -108,9 +108,17 @@ type EventFailedOk struct {
 	Reason string
 }
 
+// EventWin is sent when a game ends. Player numbers and scores are from the
+// perspective of the client receiving the event.
 type EventWin struct {
 	Event
-	Points int8
+	Points    int8 // Points awarded to the winner, including the value of the doubling cube.
+	Winner    int8
+	WinType   int8 // 1 - single game, 2 - gammon, 3 - backgammon.
+	CubeValue int8
+	Score1    int
+	Score2    int
+	MatchOver bool
 }
 
 type EventSettings struct {
@@ -157,6 +165,22 @@ type EventHistory struct {
 	CasualTabulaMulti      int
 }
 
+type EventDrawOffer struct {
+	Event
+}
+
+type EventDraw struct {
+	Event
+}
+
+type EventPaused struct {
+	Event
+}
+
+type EventResumed struct {
+	Event
+}
+
 func DecodeEvent(message []byte) (interface{}, error) {
 	e := &Event{}
 	err := json.Unmarshal(message, e)
@@ -204,6 +228,14 @@ func DecodeEvent(message []byte) (interface{}, error) {
 		ev = &EventReplay{}
 	case EventTypeHistory:
 		ev = &EventHistory{}
+	case EventTypeDrawOffer:
+		ev = &EventDrawOffer{}
+	case EventTypeDraw:
+		ev = &EventDraw{}
+	case EventTypePaused:
+		ev = &EventPaused{}
+	case EventTypeResumed:
+		ev = &EventResumed{}
 	default:
 		return nil, fmt.Errorf("failed to decode event: unknown event type: %s", e.Type)
 	}
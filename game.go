@@ -6,6 +6,7 @@ import (
 	"log"
 	"math"
 	"strconv"
+	"strings"
 	"time"
 
 	"code.rocket9labs.com/tslocum/tabula"
@@ -46,8 +47,21 @@ type Game struct {
 	DoublePlayer  int8 // Player that currently posesses the doubling cube.
 	DoubleOffered bool // Whether the current player is offering a double.
 
+	Crawford     bool // Whether the current game is the Crawford game. The cube may not be used.
+	PostCrawford bool // Whether the Crawford game has already been played.
+
 	Reroll bool // Used in acey-deucey.
 
+	NoUndo          bool // Whether players are prevented from undoing pending moves before ending their turn. Named in place of AllowUndo so the zero value allows undo.
+	AllowUnusedDice bool // Whether players may end their turn at any time. When false, players must use as many dice as possible. This is the inverse of the proposed ForceMaxMoves option.
+	AutoStart       bool // Whether the opening roll is rolled automatically once both players have joined.
+	HomeSize        int8 // Number of points in each player's home board. Checkers may only be borne off once they are all within the home board.
+	NoCube          bool // Whether the doubling cube is disabled. When true, games are played for a cube value of 1. Used instead of the proposed CubeEnabled option so the cube is enabled by default.
+	MustFillLow     bool // Whether checkers may only be borne off when no empty point in the home board may be filled instead.
+
+	LastRoll       [3]int8 // Dice rolled during the previous turn. The third die is used in tabula games.
+	LastRollPlayer int8    // Player that rolled LastRoll.
+
 	partialTurn    int8
 	partialTime    time.Time
 	partialHandled bool
@@ -67,6 +81,8 @@ func NewGame(variant int8) *Game {
 		Player2:     NewPlayer(2),
 		Points:      1,
 		DoubleValue: 1,
+
+		HomeSize: DefaultHomeSize,
 	}
 	if variant == VariantBackgammon {
 		g.Player1.Entered = true
@@ -100,8 +116,21 @@ func (g *Game) Copy(shallow bool) *Game {
 		DoublePlayer:  g.DoublePlayer,
 		DoubleOffered: g.DoubleOffered,
 
+		Crawford:     g.Crawford,
+		PostCrawford: g.PostCrawford,
+
 		Reroll: g.Reroll,
 
+		NoUndo:          g.NoUndo,
+		AllowUnusedDice: g.AllowUnusedDice,
+		AutoStart:       g.AutoStart,
+		HomeSize:        g.HomeSize,
+		NoCube:          g.NoCube,
+		MustFillLow:     g.MustFillLow,
+
+		LastRoll:       g.LastRoll,
+		LastRollPlayer: g.LastRollPlayer,
+
 		partialTurn:    g.partialTurn,
 		partialTime:    g.partialTime,
 		partialHandled: g.partialHandled,
@@ -166,6 +195,11 @@ func (g *Game) NextTurn(reroll bool) {
 		return
 	}
 
+	if g.Roll1 != 0 {
+		g.LastRoll = [3]int8{g.Roll1, g.Roll2, g.Roll3}
+		g.LastRollPlayer = g.Turn
+	}
+
 	if !reroll {
 		var nextTurn int8 = 1
 		if g.Turn == 1 {
@@ -183,6 +217,14 @@ func (g *Game) NextTurn(reroll bool) {
 }
 
 func (g *Game) Reset() {
+	if g.Winner != 0 && g.Variant == VariantBackgammon && g.Points > 1 {
+		if g.Crawford {
+			g.Crawford = false
+			g.PostCrawford = true
+		} else if !g.PostCrawford && (g.Player1.Points == g.Points-1 || g.Player2.Points == g.Points-1) {
+			g.Crawford = true
+		}
+	}
 	g.Player1.Inactive = 0
 	g.Player2.Inactive = 0
 	if g.Variant != VariantBackgammon {
@@ -199,6 +241,8 @@ func (g *Game) Reset() {
 	g.DoublePlayer = 0
 	g.DoubleOffered = false
 	g.Reroll = false
+	g.LastRoll = [3]int8{}
+	g.LastRollPlayer = 0
 	g.Winner = 0
 	g.boardStates = nil
 	g.enteredStates = nil
@@ -206,6 +250,43 @@ func (g *Game) Reset() {
 	g.partialTime = time.Time{}
 }
 
+// MatchStateInfo is a summary of the state of a match.
+type MatchStateInfo struct {
+	Points    int8 // Points required to win the match.
+	Score1    int8
+	Score2    int8
+	CubeValue int8
+	CubeOwner int8 // Player that posesses the doubling cube, or 0 when centered.
+	Crawford  bool
+	Turn      int8
+	Variant   int8
+}
+
+// MatchState returns a summary of the state of the match.
+func (g *Game) MatchState() MatchStateInfo {
+	return MatchStateInfo{
+		Points:    g.Points,
+		Score1:    g.Player1.Points,
+		Score2:    g.Player2.Points,
+		CubeValue: g.DoubleValue,
+		CubeOwner: g.DoublePlayer,
+		Crawford:  g.Crawford,
+		Turn:      g.Turn,
+		Variant:   g.Variant,
+	}
+}
+
+// CubeDead returns whether the doubling cube is irrelevant to the outcome of
+// the match. This is the case when the cube may not be used, or when either
+// player would win the match by winning the current game at the current cube
+// value.
+func (g *Game) CubeDead() bool {
+	if g.NoCube || g.Variant != VariantBackgammon || g.Points <= 1 || g.Crawford {
+		return true
+	}
+	return g.Points-g.Player1.Points <= g.DoubleValue || g.Points-g.Player2.Points <= g.DoubleValue
+}
+
 func (g *Game) turnPlayer() Player {
 	switch g.Turn {
 	case 2:
@@ -224,6 +305,12 @@ func (g *Game) opponentPlayer() Player {
 	}
 }
 
+// SecondHalf returns whether all of the provided player's checkers have reached
+// the second half of the board in a tabula game. Both players move from space 1
+// towards space 24, so this becomes true once the player has no checkers on the
+// bar, no checkers waiting to enter and no checkers on spaces 1 through 12. A
+// checker on space 12 prevents bearing off, while a checker on space 13 does not.
+// This always returns false for other variants.
 func (g *Game) SecondHalf(player int8, local bool) bool {
 	if g.Variant != VariantTabula {
 		return false
@@ -269,6 +356,15 @@ func (g *Game) setEntered() {
 }
 
 func (g *Game) addMove(move []int8) bool {
+	if (g.Turn != 1 && g.Turn != 2) || !ValidSpace(move[0]) || !ValidSpace(move[1]) {
+		return false
+	} else if move[1] == SpaceBarPlayer || move[1] == SpaceBarOpponent {
+		// Checkers are only moved to the bar when hit.
+		return false
+	} else if PlayerCheckers(g.Board[move[0]], g.Turn) == 0 {
+		return false
+	}
+
 	opponentCheckers := OpponentCheckers(g.Board[move[1]], g.Turn)
 	if opponentCheckers > 1 {
 		return false
@@ -350,6 +446,39 @@ func (g *Game) ExpandMove(move []int8, currentSpace int8, moves [][]int8, local
 	return nil, false
 }
 
+// NormalizeMoves returns the provided moves ordered so that each move is legal
+// once the moves before it have been played. False is returned when the moves
+// may not be played in any order.
+func (g *Game) NormalizeMoves(moves [][]int8, local bool) ([][]int8, bool) {
+	if len(moves) == 0 {
+		return nil, true
+	}
+	for i, move := range moves {
+		var legal bool
+		for _, lm := range g.LegalMoves(local) {
+			if lm[0] == move[0] && lm[1] == move[1] {
+				legal = true
+				break
+			}
+		}
+		if !legal {
+			continue
+		}
+		gc := g.Copy(true)
+		if !gc.addMove(move) {
+			continue
+		}
+		remaining := make([][]int8, 0, len(moves)-1)
+		remaining = append(remaining, moves[:i]...)
+		remaining = append(remaining, moves[i+1:]...)
+		ordered, ok := gc.NormalizeMoves(remaining, local)
+		if ok {
+			return append([][]int8{{move[0], move[1]}}, ordered...), true
+		}
+	}
+	return nil, false
+}
+
 // AddMoves adds moves to the game state.  Adding a backwards move will remove the equivalent existing move.
 func (g *Game) AddMoves(moves [][]int8, local bool) (bool, [][]int8) {
 	if g.Player1.Name == "" || g.Player2.Name == "" || g.Winner != 0 {
@@ -361,39 +490,52 @@ func (g *Game) AddMoves(moves [][]int8, local bool) (bool, [][]int8) {
 
 	gameCopy := g.Copy(false)
 
-	validateOffset := 0
-VALIDATEMOVES:
-	for _, move := range moves {
-		l := gameCopy.LegalMoves(local)
-		for _, lm := range l {
-			if lm[0] == move[0] && lm[1] == move[1] {
-				addMoves = append(addMoves, []int8{move[0], move[1]})
-				continue VALIDATEMOVES
+	// Accept moves submitted in any order.
+	var ordered [][]int8
+	var normalized bool
+	if len(moves) > 1 {
+		ordered, normalized = g.NormalizeMoves(moves, local)
+	}
+	if normalized {
+		addMoves = ordered
+	} else {
+		validateOffset := 0
+	VALIDATEMOVES:
+		for _, move := range moves {
+			l := gameCopy.LegalMoves(local)
+			for _, lm := range l {
+				if lm[0] == move[0] && lm[1] == move[1] {
+					addMoves = append(addMoves, []int8{move[0], move[1]})
+					continue VALIDATEMOVES
+				}
 			}
-		}
 
-		if len(gameCopy.Moves) > 0 {
-			i := len(gameCopy.Moves) - 1 - validateOffset
-			if i < 0 {
-				return false, nil
+			if len(gameCopy.Moves) > 0 {
+				i := len(gameCopy.Moves) - 1 - validateOffset
+				if i < 0 {
+					return false, nil
+				}
+				gameMove := gameCopy.Moves[i]
+				if move[0] == gameMove[1] && move[1] == gameMove[0] {
+					if g.NoUndo {
+						return false, nil
+					}
+					undoMoves = append(undoMoves, []int8{gameMove[1], gameMove[0]})
+					validateOffset++
+					continue VALIDATEMOVES
+				}
 			}
-			gameMove := gameCopy.Moves[i]
-			if move[0] == gameMove[1] && move[1] == gameMove[0] {
-				undoMoves = append(undoMoves, []int8{gameMove[1], gameMove[0]})
-				validateOffset++
+
+			expandedMoves, ok := g.ExpandMove(move, move[0], nil, local)
+			if ok {
+				for _, expanded := range expandedMoves {
+					addMoves = append(addMoves, []int8{expanded[0], expanded[1]})
+				}
 				continue VALIDATEMOVES
 			}
-		}
 
-		expandedMoves, ok := g.ExpandMove(move, move[0], nil, local)
-		if ok {
-			for _, expanded := range expandedMoves {
-				addMoves = append(addMoves, []int8{expanded[0], expanded[1]})
-			}
-			continue VALIDATEMOVES
+			return false, nil
 		}
-
-		return false, nil
 	}
 
 	if len(addMoves) != 0 && len(undoMoves) != 0 {
@@ -475,6 +617,9 @@ ADDMOVES:
 	}
 }
 
+// DiceRolls returns the dice which have not yet been used by the pending moves.
+// A checker borne off using a die greater than needed uses the highest such
+// die, so the result does not depend on the order the dice were rolled in.
 func (g *Game) DiceRolls() []int8 {
 	rolls := []int8{
 		g.Roll1,
@@ -486,44 +631,112 @@ func (g *Game) DiceRolls() []int8 {
 		rolls = append(rolls, g.Roll1, g.Roll2)
 	}
 
-	useDiceRoll := func(from, to int8) bool {
-		if to == SpaceHomePlayer || to == SpaceHomeOpponent {
-			needRoll := from
-			if to == SpaceHomeOpponent || g.Variant == VariantTabula {
-				needRoll = 25 - from
-			}
-			for i, roll := range rolls {
-				if roll == needRoll {
-					rolls = append(rolls[:i], rolls[i+1:]...)
-					return true
-				}
-			}
-			for i, roll := range rolls {
-				if roll > needRoll {
-					rolls = append(rolls[:i], rolls[i+1:]...)
-					return true
-				}
-			}
-			return false
+	for _, move := range g.Moves {
+		var die int8
+		rolls, die = g.useDiceRoll(rolls, move[0], move[1])
+		if die == 0 {
+			return nil
 		}
+	}
+
+	return rolls
+}
 
-		diff := SpaceDiff(from, to, g.Variant)
+// useDiceRoll removes the die used to move a checker between the provided
+// spaces from the provided dice. The remaining dice and the value of the die
+// used are returned. A die value of 0 is returned when no die may be used.
+func (g *Game) useDiceRoll(rolls []int8, from int8, to int8) ([]int8, int8) {
+	if to == SpaceHomePlayer || to == SpaceHomeOpponent {
+		needRoll := from
+		if to == SpaceHomeOpponent || g.Variant == VariantTabula {
+			needRoll = 25 - from
+		}
 		for i, roll := range rolls {
-			if roll == diff {
-				rolls = append(rolls[:i], rolls[i+1:]...)
-				return true
+			if roll == needRoll {
+				return append(rolls[:i], rolls[i+1:]...), roll
 			}
 		}
-		return false
+		highest := -1
+		for i, roll := range rolls {
+			if roll > needRoll && (highest == -1 || roll > rolls[highest]) {
+				highest = i
+			}
+		}
+		if highest == -1 {
+			return rolls, 0
+		}
+		roll := rolls[highest]
+		return append(rolls[:highest], rolls[highest+1:]...), roll
 	}
 
+	diff := SpaceDiff(from, to, g.Variant)
+	for i, roll := range rolls {
+		if roll == diff {
+			return append(rolls[:i], rolls[i+1:]...), roll
+		}
+	}
+	return rolls, 0
+}
+
+// MoveDie is a single move paired with the die used to play it.
+type MoveDie struct {
+	Move []int8
+	Die  int8
+}
+
+// MoveDiceAssignment returns each pending move paired with the die it used.
+// Moves which used more than one die are split into a move for each die. Nil
+// is returned when the pending moves could not have been played using the
+// rolled dice.
+func (g *Game) MoveDiceAssignment(local bool) []MoveDie {
+	rolls := []int8{g.Roll1, g.Roll2}
+	if g.Variant == VariantTabula {
+		rolls = append(rolls, g.Roll3)
+	} else if g.Roll1 == g.Roll2 {
+		rolls = append(rolls, g.Roll1, g.Roll2)
+	}
+	var assignment []MoveDie
 	for _, move := range g.Moves {
-		if !useDiceRoll(move[0], move[1]) {
+		var hops []MoveDie
+		rolls, hops = g.assignDice(rolls, move[0], move[1])
+		if hops == nil {
 			return nil
 		}
+		assignment = append(assignment, hops...)
+	}
+	if local {
+		for i := range assignment {
+			assignment[i].Move = FlipMoves([][]int8{assignment[i].Move}, g.Turn, g.Variant)[0]
+		}
 	}
+	return assignment
+}
 
-	return rolls
+// assignDice returns the moves needed to move a checker between the provided
+// spaces using the provided dice, along with the remaining dice. Nil moves are
+// returned when the dice may not be used to play the move.
+func (g *Game) assignDice(rolls []int8, from int8, to int8) ([]int8, []MoveDie) {
+	remaining, die := g.useDiceRoll(append([]int8(nil), rolls...), from, to)
+	if die != 0 {
+		return remaining, []MoveDie{{Move: []int8{from, to}, Die: die}}
+	} else if from < 1 || from > 24 || to < 1 || to > 24 {
+		return rolls, nil
+	}
+	for i, roll := range rolls {
+		if roll >= SpaceDiff(from, to, g.Variant) {
+			continue
+		}
+		via := from - roll
+		if to > from {
+			via = from + roll
+		}
+		remaining := append(append([]int8(nil), rolls[:i]...), rolls[i+1:]...)
+		remaining, hops := g.assignDice(remaining, via, to)
+		if hops != nil {
+			return remaining, append([]MoveDie{{Move: []int8{from, via}, Die: roll}}, hops...)
+		}
+	}
+	return rolls, nil
 }
 
 func (g *Game) HaveDiceRoll(from int8, to int8) int8 {
@@ -545,6 +758,9 @@ func (g *Game) HaveDiceRoll(from int8, to int8) int8 {
 	return c
 }
 
+// HaveBearOffDiceRoll returns the number of unused dice which may bear off a
+// checker the provided distance from home. In backgammon games, any die greater
+// than the distance is counted.
 func (g *Game) HaveBearOffDiceRoll(diff int8) int8 {
 	if diff == 0 {
 		return 0
@@ -558,6 +774,10 @@ func (g *Game) HaveBearOffDiceRoll(diff int8) int8 {
 	return c
 }
 
+// LegalMoves returns the moves the current player may play. Only moves which
+// allow the player to use as many dice as possible are returned unless
+// AllowUnusedDice is true, in which case any move playable using one of the
+// remaining dice is returned.
 func (g *Game) LegalMoves(local bool) [][]int8 {
 	if g.Turn == 0 {
 		return nil
@@ -565,14 +785,106 @@ func (g *Game) LegalMoves(local bool) [][]int8 {
 	b, ok := g.TabulaBoard()
 	if !ok {
 		return nil
+	} else if !g.AllowUnusedDice {
+		return g.fillLowMoves(g.availableMoves(b))
+	}
+	var moves [][]int8
+	var checked []int8
+ROLLS:
+	for _, roll := range g.DiceRolls() {
+		for _, r := range checked {
+			if r == roll {
+				continue ROLLS
+			}
+		}
+		checked = append(checked, roll)
+
+		single := b
+		single[tabula.SpaceRoll1], single[tabula.SpaceRoll2], single[tabula.SpaceRoll3], single[tabula.SpaceRoll4] = roll, 0, 0, 0
+	MOVES:
+		for _, move := range g.availableMoves(single) {
+			for _, m := range moves {
+				if m[0] == move[0] && m[1] == move[1] {
+					continue MOVES
+				}
+			}
+			moves = append(moves, move)
+		}
+	}
+	return g.fillLowMoves(moves)
+}
+
+// fillLowMoves removes the moves which bear off a checker from a point above
+// an empty point in the current player's home board when MustFillLow is
+// enabled and another of the provided moves fills the empty point.
+func (g *Game) fillLowMoves(moves [][]int8) [][]int8 {
+	if !g.MustFillLow {
+		return moves
+	}
+	var gap int8
+FILL:
+	for point := int8(1); point <= g.homeSize(); point++ {
+		space := playerSpace(point, g.Turn, g.Variant)
+		if g.Board[space] != 0 {
+			continue
+		}
+		for _, move := range moves {
+			if move[1] == space {
+				gap = point
+				break FILL
+			}
+		}
+	}
+	if gap == 0 {
+		return moves
+	}
+	var filtered [][]int8
+	for _, move := range moves {
+		if move[1] == SpaceHomePlayer || move[1] == SpaceHomeOpponent {
+			var below bool
+			for point := int8(1); point < gap; point++ {
+				if move[0] == playerSpace(point, g.Turn, g.Variant) {
+					below = true
+					break
+				}
+			}
+			if !below {
+				continue
+			}
+		}
+		filtered = append(filtered, move)
 	}
+	return filtered
+}
+
+// TurnStart returns a copy of the game as it was before the pending moves were
+// played.
+func (g *Game) TurnStart() *Game {
+	gc := g.Copy(true)
+	if len(g.boardStates) != 0 {
+		copy(gc.Board, g.boardStates[0])
+		gc.Player1.Entered, gc.Player2.Entered = g.enteredStates[0][0], g.enteredStates[0][1]
+	}
+	gc.Moves = nil
+	return gc
+}
+
+// LegalMovesForPlayer returns the moves the current player may play, numbered
+// from the perspective of the provided player.
+func (g *Game) LegalMovesForPlayer(player int8, local bool) [][]int8 {
+	return FlipMoves(g.LegalMoves(local), player, g.Variant)
+}
+
+// availableMoves returns the distinct moves within the sequences of moves
+// available on the provided board.
+func (g *Game) availableMoves(b tabula.Board) [][]int8 {
 	barSpace := SpaceBarPlayer
 	if g.Turn == 2 {
 		barSpace = SpaceBarOpponent
 	}
 	onBar := g.Board[barSpace] != 0
 	available, _ := b.Available(g.Turn)
-	mayBearOff := b.MayBearOff(g.Turn)
+	mayBearOff := b.MayBearOff(g.Turn) && (g.homeSize() == DefaultHomeSize || g.MayBearOff(g.Turn, false))
 	var moves [][]int8
 	for i := range available {
 		for j := range available[i] {
@@ -596,6 +908,242 @@ func (g *Game) LegalMoves(local bool) [][]int8 {
 	return moves
 }
 
+// LegalSubMoves returns each legal single-die move which may be played first
+// while still allowing the player to use the greatest possible number of dice.
+func (g *Game) LegalSubMoves(local bool) [][]int8 {
+	max := g.maxMoveCount()
+	if max == 0 {
+		return nil
+	}
+	var moves [][]int8
+	for _, move := range g.LegalMoves(local) {
+		gc := g.Copy(true)
+		if !gc.addMove(move) || gc.maxMoveCount() != max-1 {
+			continue
+		}
+		moves = append(moves, move)
+	}
+	return moves
+}
+
+// SingleDiePositions returns a copy of the game after each legal move which
+// uses a single die, as returned by LegalSubMoves. Moves which result in the
+// same board are only included once.
+func (g *Game) SingleDiePositions(local bool) []*Game {
+	var positions []*Game
+MOVES:
+	for _, move := range g.LegalSubMoves(local) {
+		gc := g.Copy(false)
+		if !gc.addMove(move) {
+			continue
+		}
+		for _, position := range positions {
+			if equalBoards(position.Board, gc.Board) {
+				continue MOVES
+			}
+		}
+		positions = append(positions, gc)
+	}
+	return positions
+}
+
+// UnusedDice returns the number of remaining dice the current player will be
+// unable to use, even when playing as many dice as possible. A count of dice
+// does not depend on perspective, so local is ignored.
+func (g *Game) UnusedDice(local bool) int {
+	if g.Turn == 0 || g.Roll1 == 0 {
+		return 0
+	}
+	return len(g.DiceRolls()) - g.maxMoveCount()
+}
+
+// MovableSpaces returns the distinct spaces from which the current player may
+// move a checker. When the player has checkers on the bar, only the bar is returned.
+func (g *Game) MovableSpaces(local bool) []int8 {
+	var spaces []int8
+	for _, m := range g.LegalMoves(local) {
+		var found bool
+		for _, space := range spaces {
+			if space == m[0] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			spaces = append(spaces, m[0])
+		}
+	}
+	return spaces
+}
+
+// ClosedOut returns whether the provided player has checkers on the bar and
+// every entry space is blocked by the opponent.
+func (g *Game) ClosedOut(player int8) bool {
+	if g.BarPips(player) == 0 {
+		return false
+	}
+	for roll := int8(1); roll <= 6; roll++ {
+		if OpponentCheckers(g.Board[EntrySpace(player, roll, g.Variant)], player) < 2 {
+			return false
+		}
+	}
+	return true
+}
+
+// Danced returns whether the provided player has rolled but is unable to enter
+// a checker from the bar using any of the dice.
+func (g *Game) Danced(player int8) bool {
+	if g.Turn != player || g.Roll1 == 0 || len(g.Moves) != 0 || g.BarPips(player) == 0 {
+		return false
+	}
+	for _, roll := range g.DiceRolls() {
+		if OpponentCheckers(g.Board[EntrySpace(player, roll, g.Variant)], player) < 2 {
+			return false
+		}
+	}
+	return true
+}
+
+// MayRoll returns whether the provided player may roll the dice. During the
+// opening roll each player rolls a single die, rolling again when the dice
+// match. Otherwise, the player may roll at the start of their turn unless a
+// double has been offered. The Crawford game does not restrict rolling, as
+// only the cube is disabled.
+func (g *Game) MayRoll(player int8) bool {
+	if g.Winner != 0 || g.DoubleOffered {
+		return false
+	}
+	switch g.Turn {
+	case 0:
+		if player == 1 {
+			return g.Player2.Name != "" && (g.Roll1 == 0 || (g.Roll1 == g.Roll2))
+		} else if player == 2 {
+			return g.Player1.Name != "" && (g.Roll2 == 0 || (g.Roll1 == g.Roll2))
+		}
+		return false
+	case 1:
+		return player == g.Turn && g.Player2.Name != "" && g.Roll1 == 0
+	case 2:
+		return player == g.Turn && g.Player1.Name != "" && g.Roll1 == 0
+	default:
+		log.Panicf("unknown turn %d", g.Turn)
+		return false
+	}
+}
+
+// DanceRolls returns how many of the 36 combinations of two dice would fail to
+// enter a checker of the provided player from the bar.
+func (g *Game) DanceRolls(player int8) int {
+	var blocked int
+	for roll := int8(1); roll <= 6; roll++ {
+		if OpponentCheckers(g.Board[EntrySpace(player, roll, g.Variant)], player) >= 2 {
+			blocked++
+		}
+	}
+	return blocked * blocked
+}
+
+// IsRace returns whether the players' checkers have moved past each other, so
+// that no further contact is possible. Tabula games are never considered a race
+// because both players move in the same direction.
+func (g *Game) IsRace() bool {
+	if g.Variant == VariantTabula {
+		return false
+	}
+	var max1, min2 int8 = 0, 25
+	if g.BarPips(1) != 0 || (!g.Player1.Entered && g.Board[SpaceHomePlayer] != 0) {
+		max1 = 25
+	}
+	if g.BarPips(2) != 0 || (!g.Player2.Entered && g.Board[SpaceHomeOpponent] != 0) {
+		min2 = 0
+	}
+	for space := int8(1); space <= 24; space++ {
+		if g.Board[space] > 0 && space > max1 {
+			max1 = space
+		} else if g.Board[space] < 0 && space < min2 {
+			min2 = space
+		}
+	}
+	return max1 < min2
+}
+
+// WinType returns 1 when the game was won normally, 2 when it was won by a
+// gammon and 3 when it was won by a backgammon. Gammons and backgammons are only
+// awarded in backgammon games. 0 is returned when the game has not been won.
+func (g *Game) WinType() int8 {
+	if g.Winner == 0 {
+		return 0
+	} else if g.Variant != VariantBackgammon {
+		return 1
+	}
+	loser, loserHome := int8(2), SpaceHomeOpponent
+	if g.Winner == 2 {
+		loser, loserHome = 1, SpaceHomePlayer
+	}
+	if PlayerCheckers(g.Board[loserHome], loser) != 0 {
+		return 1
+	} else if g.BarPips(loser) != 0 {
+		return 3
+	}
+	winType := int8(2)
+	homeStart, homeEnd := HomeRange(g.Winner, g.Variant)
+	IterateSpaces(homeStart, homeEnd, g.Variant, func(space int8, spaceCount int8) {
+		if PlayerCheckers(g.Board[space], loser) != 0 {
+			winType = 3
+		}
+	})
+	return winType
+}
+
+// Stake returns the number of points awarded to the winner of the game,
+// including the value of the doubling cube. In acey-deucey games, a point is
+// awarded for each checker the loser has not borne off. 0 is returned when the
+// game has not been won.
+func (g *Game) Stake() int8 {
+	cube := g.DoubleValue
+	if g.NoCube {
+		cube = 1
+	}
+	if g.Winner == 0 {
+		return 0
+	} else if g.Variant != VariantAceyDeucey {
+		return g.WinType() * cube
+	}
+	loser := opponentNumber(g.Winner)
+	entered := g.Player1.Entered
+	if loser == 2 {
+		entered = g.Player2.Entered
+	}
+	var points int8
+	for space := int8(0); space < BoardSpaces; space++ {
+		if (space == SpaceHomePlayer || space == SpaceHomeOpponent) && entered {
+			continue
+		}
+		points += PlayerCheckers(g.Board[space], loser)
+	}
+	return points * cube
+}
+
+// WouldScore returns the number of points which would be awarded to the
+// provided winner if the game ended now with the provided win type, including
+// the value of the doubling cube. In acey-deucey games, the win type is
+// ignored and a point is awarded for each checker the loser has not borne
+// off. The game is not modified.
+func (g *Game) WouldScore(winner int8, winType int8) int8 {
+	if winner != 1 && winner != 2 {
+		return 0
+	} else if g.Variant == VariantAceyDeucey {
+		gc := g.Copy(true)
+		gc.Winner = winner
+		return gc.Stake()
+	}
+	cube := g.DoubleValue
+	if g.NoCube {
+		cube = 1
+	}
+	return winType * cube
+}
+
 // MayBearOff returns whether the provided player may bear checkers off of the board.
 func (g *Game) MayBearOff(player int8, local bool) bool {
 	if PlayerCheckers(g.Board[SpaceBarPlayer], player) > 0 || PlayerCheckers(g.Board[SpaceBarOpponent], player) > 0 {
@@ -606,9 +1154,15 @@ func (g *Game) MayBearOff(player int8, local bool) bool {
 		return g.SecondHalf(player, local)
 	}
 
-	homeStart, homeEnd := int8(1), int8(6)
+	homeSize := g.homeSize()
+	homeStart, homeEnd := int8(1), homeSize
 	if !local {
 		homeStart, homeEnd = HomeRange(player, g.Variant)
+		if homeStart > homeEnd {
+			homeEnd = homeStart - homeSize + 1
+		} else {
+			homeEnd = homeStart + homeSize - 1
+		}
 		homeStart, homeEnd = minInt(homeStart, homeEnd), maxInt(homeStart, homeEnd)
 	}
 	for i := int8(1); i <= 24; i++ {
@@ -619,7 +1173,141 @@ func (g *Game) MayBearOff(player int8, local bool) bool {
 	return true
 }
 
+// homeSize returns the number of points in each player's home board. Games
+// which do not specify a home board size use DefaultHomeSize.
+func (g *Game) homeSize() int8 {
+	if g.HomeSize < 1 || g.HomeSize > DefaultHomeSize {
+		return DefaultHomeSize
+	}
+	return g.HomeSize
+}
+
+// BarPips returns the pip cost of the provided player's checkers on the bar.
+// Every variant enters a checker at the start of its path, so each checker
+// on the bar costs 25 pips regardless of where the variant enters.
+func (g *Game) BarPips(player int8) int {
+	return int(PlayerCheckers(g.Board[SpaceBarPlayer], player)+PlayerCheckers(g.Board[SpaceBarOpponent], player)) * 25
+}
+
+// PipCount returns the number of pips the provided player must move to bear
+// off all of their checkers.
+func (g *Game) PipCount(player int8) int {
+	pips := g.BarPips(player)
+	if player == 1 && !g.Player1.Entered {
+		pips += int(PlayerCheckers(g.Board[SpaceHomePlayer], player)) * 25
+	} else if player == 2 && !g.Player2.Entered {
+		pips += int(PlayerCheckers(g.Board[SpaceHomeOpponent], player)) * 25
+	}
+	for point := int8(1); point <= 24; point++ {
+		pips += int(PlayerCheckers(g.Board[playerSpace(point, player, g.Variant)], player)) * int(point)
+	}
+	return pips
+}
+
+// PipCountAfterBestMove returns the current player's pip count after playing
+// the legal sequence of moves using the remaining dice which reduces their pip
+// count the most. The current pip count is returned when no legal move exists.
+func (g *Game) PipCountAfterBestMove(local bool) int {
+	if g.Turn == 0 {
+		return 0
+	}
+	moves := g.bestSequence(func(gc *Game) float64 {
+		return -float64(gc.PipCount(g.Turn))
+	})
+	gc := g.Copy(true)
+	for _, move := range moves {
+		gc.addMove(move)
+	}
+	return gc.PipCount(g.Turn)
+}
+
+// OccupiedSpaces returns each space, including the bar and home spaces, where
+// the provided player has at least one checker.
+func (g *Game) OccupiedSpaces(player int8) []int8 {
+	var spaces []int8
+	for space := int8(0); space < BoardSpaces; space++ {
+		if PlayerCheckers(g.Board[space], player) != 0 {
+			spaces = append(spaces, space)
+		}
+	}
+	return spaces
+}
+
+// CheckerMap returns the number of checkers the provided player has on each
+// occupied space, including the bar and home spaces.
+func (g *Game) CheckerMap(player int8) map[int8]int8 {
+	checkers := make(map[int8]int8)
+	for _, space := range g.OccupiedSpaces(player) {
+		checkers[space] = PlayerCheckers(g.Board[space], player)
+	}
+	return checkers
+}
+
+// OwnershipBoard returns the player who has checkers on each space of the
+// board, including the bar and home spaces, or 0 when a space is empty. The
+// number of checkers on each space is returned by CountBoard.
+func (g *Game) OwnershipBoard() []int8 {
+	owners := make([]int8, len(g.Board))
+	for space, checkers := range g.Board {
+		if checkers > 0 {
+			owners[space] = 1
+		} else if checkers < 0 {
+			owners[space] = 2
+		}
+	}
+	return owners
+}
+
+// CountBoard returns the number of checkers on each space of the board,
+// including the bar and home spaces. The player who owns the checkers on each
+// space is returned by OwnershipBoard.
+func (g *Game) CountBoard() []int8 {
+	counts := make([]int8, len(g.Board))
+	for space, checkers := range g.Board {
+		if checkers < 0 {
+			checkers *= -1
+		}
+		counts[space] = checkers
+	}
+	return counts
+}
+
+// OffCount returns the number of checkers the provided player has borne off.
+func (g *Game) OffCount(player int8) int8 {
+	home, entered := SpaceHomePlayer, g.Player1.Entered
+	if player == 2 {
+		home, entered = SpaceHomeOpponent, g.Player2.Entered
+	}
+	if !entered {
+		return 0
+	}
+	return PlayerCheckers(g.Board[home], player)
+}
+
+// CheckersToBearOff returns the number of checkers the provided player must
+// still bear off to win the game.
+func (g *Game) CheckersToBearOff(player int8) int8 {
+	return CheckerCount(g.Variant) - g.OffCount(player)
+}
+
+// StartingPipCount returns the pip count of each player at the start of a
+// game of the provided variant.
+func StartingPipCount(variant int8) int {
+	return NewGame(variant).PipCount(1)
+}
+
+// CheckerProgress returns the number of pips the provided player's checkers
+// have traveled since the start of the game, including checkers which have
+// been borne off. Pips lost when a checker is hit are subtracted.
+func (g *Game) CheckerProgress(player int8) int {
+	return StartingPipCount(g.Variant) - g.PipCount(player)
+}
+
 func (g *Game) RenderSpace(player int8, space int8, spaceValue int8, legalMoves [][]int8) []byte {
+	return g.renderSpace(player, space, spaceValue, legalMoves, defaultMaxStack)
+}
+
+func (g *Game) renderSpace(player int8, space int8, spaceValue int8, legalMoves [][]int8, maxStack int8) []byte {
 	var playerColor = "x"
 	var opponentColor = "o"
 	if player == 2 {
@@ -653,16 +1341,15 @@ func (g *Game) RenderSpace(player int8, space int8, spaceValue int8, legalMoves
 		top = !top
 	}
 
-	var firstDigit int8 = 4
-	var secondDigit int8 = 5
+	firstDigit := maxStack - 1
+	secondDigit := maxStack
 	if !top {
-		firstDigit = 5
-		secondDigit = 4
+		firstDigit, secondDigit = secondDigit, firstDigit
 	}
 
 	var firstNumeral string
 	var secondNumeral string
-	if abs > 5 {
+	if abs > maxStack {
 		if abs > 9 {
 			firstNumeral = "1"
 		} else {
@@ -681,8 +1368,8 @@ func (g *Game) RenderSpace(player int8, space int8, spaceValue int8, legalMoves
 		}
 	}
 
-	if abs > 5 {
-		abs = 5
+	if abs > maxStack {
+		abs = maxStack
 	}
 
 	var r []byte
@@ -694,9 +1381,89 @@ func (g *Game) RenderSpace(player int8, space int8, spaceValue int8, legalMoves
 	return append(append([]byte(" "), r...), ' ')
 }
 
+// RenderOptions controls the presentation of a board rendered by RenderBoard.
+// The board data itself is not affected.
+type RenderOptions struct {
+	HomeLeft bool // Render the board mirrored, with the player's home board on the left.
+	MaxStack int8 // Maximum number of checkers drawn on a space before the count is shown as a numeral. Between 2 and 5.
+}
+
+// defaultMaxStack is the maximum number of checkers drawn on a space when
+// MaxStack is not specified. Each half of the board has room for five checkers.
+const defaultMaxStack = 5
+
+// BoardState returns the board rendered as text using the default options.
 func (g *Game) BoardState(player int8, local bool) []byte {
+	return g.RenderBoard(player, local, nil)
+}
+
+// StatusLine returns the dice, the doubling cube and the pip counts rendered
+// as a single line of text from the perspective of the provided player, such
+// as "Your roll: 6-3 | Cube: 2 (yours) | Pips: 142-151". Spectators are
+// shown the game from the perspective of player 1.
+func (g *Game) StatusLine(player int8) string {
+	if player != 2 {
+		player = 1
+	}
+	opponent := opponentNumber(player)
+
+	var parts []string
+	switch {
+	case g.Winner == player:
+		parts = append(parts, "You won")
+	case g.Winner != 0:
+		parts = append(parts, "Opponent won")
+	case g.Turn == 0:
+		parts = append(parts, "Waiting for the opening roll")
+	case g.Roll1 == 0 && g.Turn == player:
+		parts = append(parts, "Your turn to roll")
+	case g.Roll1 == 0:
+		parts = append(parts, "Opponent's turn to roll")
+	default:
+		dice := fmt.Sprintf("%d-%d", g.Roll1, g.Roll2)
+		if g.Variant == VariantTabula && g.Roll3 != 0 {
+			dice += fmt.Sprintf("-%d", g.Roll3)
+		}
+		if g.Turn == player {
+			parts = append(parts, "Your roll: "+dice)
+		} else {
+			parts = append(parts, "Opponent's roll: "+dice)
+		}
+	}
+
+	if g.Variant == VariantBackgammon && !g.NoCube {
+		owner := "centered"
+		if g.DoublePlayer == player {
+			owner = "yours"
+		} else if g.DoublePlayer == opponent {
+			owner = "opponent's"
+		}
+		parts = append(parts, fmt.Sprintf("Cube: %d (%s)", g.DoubleValue, owner))
+	}
+
+	parts = append(parts, fmt.Sprintf("Pips: %d-%d", g.PipCount(player), g.PipCount(opponent)))
+	return strings.Join(parts, " | ")
+}
+
+// RenderBoard returns the board rendered as text from the perspective of the
+// provided player, who is always shown at the bottom of the board.
+func (g *Game) RenderBoard(player int8, local bool, options *RenderOptions) []byte {
 	var t bytes.Buffer
 
+	var homeLeft bool
+	maxStack := int8(defaultMaxStack)
+	if options != nil {
+		homeLeft = options.HomeLeft
+		if options.MaxStack != 0 {
+			maxStack = options.MaxStack
+		}
+	}
+	if maxStack < 2 {
+		maxStack = 2
+	} else if maxStack > defaultMaxStack {
+		maxStack = defaultMaxStack
+	}
+
 	playerRating := "0"
 	opponentRating := "0"
 
@@ -728,7 +1495,7 @@ func (g *Game) BoardState(player int8, local bool) []byte {
 		opponentRoll = g.Roll1
 	}
 
-	if white {
+	if white != homeLeft {
 		t.Write(boardTopWhite)
 	} else {
 		t.Write(boardTopBlack)
@@ -745,9 +1512,13 @@ func (g *Game) BoardState(player int8, local bool) []byte {
 
 		if col == -1 {
 			if row <= 4 {
-				return g.RenderSpace(player, SpaceBarOpponent, spaceValue, legalMoves)
+				return g.renderSpace(player, SpaceBarOpponent, spaceValue, legalMoves, maxStack)
 			}
-			return g.RenderSpace(player, SpaceBarPlayer, spaceValue, legalMoves)
+			return g.renderSpace(player, SpaceBarPlayer, spaceValue, legalMoves, maxStack)
+		}
+
+		if homeLeft {
+			col = 11 - col
 		}
 
 		var space int8
@@ -767,7 +1538,7 @@ func (g *Game) BoardState(player int8, local bool) []byte {
 			return []byte("   ")
 		}
 
-		return g.RenderSpace(player, space, spaceValue, legalMoves)
+		return g.renderSpace(player, space, spaceValue, legalMoves, maxStack)
 	}
 
 	const verticalBar rune = '│'
@@ -806,10 +1577,7 @@ func (g *Game) BoardState(player int8, local bool) []byte {
 				}
 			} else if g.Turn != player {
 				if g.Roll1 > 0 {
-					t.Write([]byte(fmt.Sprintf("  %d  %d  ", g.Roll1, g.Roll2)))
-					if g.Roll3 != 0 {
-						t.Write([]byte(fmt.Sprintf("%d  ", g.Roll3)))
-					}
+					t.Write(g.renderDice())
 				} else if opponentName != "" {
 					t.Write([]byte("  -  -  "))
 				}
@@ -825,10 +1593,7 @@ func (g *Game) BoardState(player int8, local bool) []byte {
 				}
 			} else if g.Turn == player {
 				if g.Roll1 > 0 {
-					t.Write([]byte(fmt.Sprintf("  %d  %d  ", g.Roll1, g.Roll2)))
-					if g.Roll3 != 0 {
-						t.Write([]byte(fmt.Sprintf("%d  ", g.Roll3)))
-					}
+					t.Write(g.renderDice())
 				} else if playerName != "" {
 					t.Write([]byte("  -  -  "))
 				}
@@ -848,7 +1613,7 @@ func (g *Game) BoardState(player int8, local bool) []byte {
 		t.WriteByte('\n')
 	}
 
-	if white {
+	if white != homeLeft {
 		t.Write(boardBottomWhite)
 	} else {
 		t.Write(boardBottomBlack)
@@ -858,6 +1623,67 @@ func (g *Game) BoardState(player int8, local bool) []byte {
 	return t.Bytes()
 }
 
+// renderDice returns the current roll formatted for display. All four dice of
+// a doublet are shown, and dice which have already been used are enclosed in
+// parentheses.
+func (g *Game) renderDice() []byte {
+	dice := []int8{g.Roll1, g.Roll2}
+	if g.Variant == VariantTabula {
+		if g.Roll3 != 0 {
+			dice = append(dice, g.Roll3)
+		}
+	} else if g.Roll1 == g.Roll2 {
+		dice = append(dice, g.Roll1, g.Roll2)
+	}
+
+	remaining := g.DiceRolls()
+	used := make([]bool, len(dice))
+	for i := len(dice) - 1; i >= 0; i-- {
+		used[i] = true
+		for j, roll := range remaining {
+			if roll == dice[i] {
+				used[i] = false
+				remaining = append(remaining[:j], remaining[j+1:]...)
+				break
+			}
+		}
+	}
+
+	var b bytes.Buffer
+	for i, roll := range dice {
+		if used[i] {
+			b.WriteString(fmt.Sprintf("  (%d)", roll))
+		} else {
+			b.WriteString(fmt.Sprintf("  %d", roll))
+		}
+	}
+	b.WriteString("  ")
+	return b.Bytes()
+}
+
+// entryDistance returns the distance from the provided player's bar to the
+// provided space. See EntrySpace for the inverse.
+func entryDistance(player int8, space int8, variant int8) int8 {
+	if player == 1 && variant != VariantTabula {
+		return 25 - space
+	}
+	return space
+}
+
+// BarEntryDistance returns the die value the provided player needs to enter a
+// checker from the bar onto the provided space, or 0 when checkers may not
+// enter onto the space.
+func (g *Game) BarEntryDistance(player int8, entrySpace int8) int8 {
+	if entrySpace < 1 || entrySpace > 24 {
+		return 0
+	}
+	distance := entryDistance(player, entrySpace, g.Variant)
+	if distance < 1 || distance > 6 {
+		return 0
+	}
+	return distance
+}
+
 func SpaceDiff(from int8, to int8, variant int8) int8 {
 	switch {
 	case from < 0 || from > 27 || to < 0 || to > 27:
@@ -886,12 +1712,9 @@ func SpaceDiff(from int8, to int8, variant int8) int8 {
 		}
 		return 0
 	case from == SpaceBarPlayer:
-		if variant == VariantTabula {
-			return to
-		}
-		return 25 - to
+		return entryDistance(1, to, variant)
 	case from == SpaceBarOpponent:
-		return to
+		return entryDistance(2, to, variant)
 	default:
 		diff := to - from
 		if diff < 0 {
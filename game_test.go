@@ -0,0 +1,569 @@
+package bgammon
+
+import (
+	"reflect"
+	"testing"
+)
+
+// newTestGame returns a game in progress with a copy of the provided board,
+// player on roll and dice.
+func newTestGame(variant int8, board []int8, turn int8, roll1 int8, roll2 int8) *Game {
+	g := NewGame(variant)
+	g.Player1.Name, g.Player2.Name = "Player 1", "Player 2"
+	copy(g.Board, board)
+	g.Turn = turn
+	g.Roll1, g.Roll2 = roll1, roll2
+	return g
+}
+
+// playerBoard returns a board with the provided player's checkers placed on
+// points numbered from their perspective. The player's remaining checkers are
+// placed in their home space, and all of the opponent's checkers are placed on
+// the opponent's 1 point.
+func playerBoard(player int8, variant int8, points map[int8]int8) []int8 {
+	sign, home := int8(1), int8(SpaceHomePlayer)
+	if player == 2 {
+		sign, home = -1, SpaceHomeOpponent
+	}
+	board := make([]int8, BoardSpaces)
+	var checkers int8
+	for point, count := range points {
+		board[playerSpace(point, player, variant)] = count * sign
+		checkers += count
+	}
+	board[home] = (15 - checkers) * sign
+	board[playerSpace(1, opponentNumber(player), variant)] = -15 * sign
+	return board
+}
+
+// formatSorted returns the provided moves sorted and formatted as text.
+func formatSorted(moves [][]int8) string {
+	sorted := make([][]int8, len(moves))
+	copy(sorted, moves)
+	SortMoves(sorted)
+	return string(FormatMoves(sorted))
+}
+
+func TestMustFillLow(t *testing.T) {
+	testCases := []struct {
+		name     string
+		board    map[int8]int8
+		roll1    int8
+		roll2    int8
+		standard string
+		mustFill string
+	}{
+		{
+			name:     "bear off above gap",
+			board:    map[int8]int8{6: 3},
+			roll1:    6,
+			roll2:    1,
+			standard: "6/5 6/off",
+			mustFill: "6/5",
+		},
+		{
+			name:     "bear off below gap",
+			board:    map[int8]int8{6: 2, 1: 1},
+			roll1:    5,
+			roll2:    1,
+			standard: "6/5 6/1 1/off",
+			mustFill: "6/5 6/1 1/off",
+		},
+		{
+			name:     "no gap to fill",
+			board:    map[int8]int8{6: 2, 5: 1},
+			roll1:    6,
+			roll2:    6,
+			standard: "6/off 5/off",
+			mustFill: "6/off 5/off",
+		},
+	}
+	for _, tc := range testCases {
+		for _, player := range []int8{1, 2} {
+			board := playerBoard(player, VariantBackgammon, tc.board)
+			g := newTestGame(VariantBackgammon, board, player, tc.roll1, tc.roll2)
+			if moves := formatSorted(g.LegalMovesForPlayer(player, false)); moves != tc.standard {
+				t.Errorf("%s: player %d: expected standard legal moves %s, got %s", tc.name, player, tc.standard, moves)
+			}
+
+			g.MustFillLow = true
+			if moves := formatSorted(g.LegalMovesForPlayer(player, false)); moves != tc.mustFill {
+				t.Errorf("%s: player %d: expected legal moves %s when low points must be filled, got %s", tc.name, player, tc.mustFill, moves)
+			}
+		}
+	}
+}
+
+func TestBarEntryThenBearOff(t *testing.T) {
+	board := make([]int8, BoardSpaces)
+	board[SpaceBarPlayer] = 1
+	board[6], board[5] = 2, 2
+	board[SpaceHomePlayer] = 10
+	board[24], board[SpaceHomeOpponent] = -2, -13
+
+	g := newTestGame(VariantBackgammon, board, 1, 6, 5)
+	if g.MayBearOff(1, false) {
+		t.Fatal("expected bearing off to be forbidden while a checker is on the bar")
+	}
+	if moves := formatSorted(g.LegalMoves(false)); moves != "bar/20 bar/19" {
+		t.Fatalf("expected only entering moves while a checker is on the bar, got %s", moves)
+	}
+
+	ok, _ := g.AddMoves([][]int8{{SpaceBarPlayer, 19}}, false)
+	if !ok {
+		t.Fatal("failed to enter checker from the bar")
+	} else if g.MayBearOff(1, false) {
+		t.Fatal("expected bearing off to be forbidden while an entered checker is outside of the home board")
+	}
+	if moves := formatSorted(g.LegalMoves(false)); moves != "19/14 6/1" {
+		t.Fatalf("expected no bearing off after entering, got %s", moves)
+	}
+}
+
+func TestBearOffOvershoot(t *testing.T) {
+	for _, variant := range []int8{VariantBackgammon, VariantTabula} {
+		for _, player := range []int8{1, 2} {
+			sign, home := int8(1), int8(SpaceHomePlayer)
+			if player == 2 {
+				sign, home = -1, SpaceHomeOpponent
+			}
+			opponent := opponentNumber(player)
+			board := make([]int8, BoardSpaces)
+			board[playerSpace(5, player, variant)] = 1 * sign
+			board[playerSpace(3, player, variant)] = 1 * sign
+			board[home] = 13 * sign
+			board[playerSpace(24, opponent, variant)] = -15 * sign
+
+			g := newTestGame(variant, board, player, 6, 1)
+			g.Player1.Entered, g.Player2.Entered = true, true
+			if !g.MayBearOff(player, false) {
+				t.Fatalf("variant %d: player %d: expected bearing off to be allowed", variant, player)
+			}
+			legalMoves := g.LegalMovesForPlayer(player, false)
+			for _, move := range legalMoves {
+				if move[0] == playerSpace(3, player, variant) && move[1] == home {
+					t.Errorf("variant %d: player %d: expected bearing off from the 3 point with a 6 to be forbidden while the 5 point is occupied, got %s", variant, player, FormatMoves(legalMoves))
+				}
+			}
+			expected := "5/4 5/off 3/2"
+			if variant == VariantTabula {
+				expected = "22/23 20/21 20/off"
+			}
+			if moves := formatSorted(legalMoves); moves != expected {
+				t.Errorf("variant %d: player %d: expected legal moves %s, got %s", variant, player, expected, moves)
+			}
+		}
+	}
+}
+
+func TestDoubletFromOnePoint(t *testing.T) {
+	board := make([]int8, BoardSpaces)
+	board[24], board[8] = 5, 10
+	board[1] = -15
+
+	g := newTestGame(VariantBackgammon, board, 1, 6, 6)
+	var found bool
+	for _, sequence := range g.sequences() {
+		if len(sequence) != 4 {
+			continue
+		}
+		found = true
+		for _, move := range sequence {
+			if move != [2]int8{24, 18} {
+				found = false
+				break
+			}
+		}
+		if found {
+			break
+		}
+	}
+	if !found {
+		t.Fatal("expected a legal sequence moving four checkers 24/18")
+	}
+
+	for i := 0; i < 4; i++ {
+		legal := g.LegalMoves(false)
+		var ok bool
+		for _, move := range legal {
+			if move[0] == 24 && move[1] == 18 {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			t.Fatalf("expected 24/18 to be legal after playing it %d times, got %s", i, FormatMoves(legal))
+		} else if ok, _ = g.AddMoves([][]int8{{24, 18}}, false); !ok {
+			t.Fatalf("failed to play 24/18 after playing it %d times", i)
+		}
+	}
+	if g.Board[24] != 1 || g.Board[18] != 4 {
+		t.Fatalf("expected one checker on 24 and four checkers on 18, got %d and %d", g.Board[24], g.Board[18])
+	}
+}
+
+func TestSecondHalf(t *testing.T) {
+	for _, player := range []int8{1, 2} {
+		sign := int8(1)
+		if player == 2 {
+			sign = -1
+		}
+		for _, tc := range []struct {
+			space    int8
+			expected bool
+		}{
+			{space: 12, expected: false},
+			{space: 13, expected: true},
+		} {
+			board := make([]int8, BoardSpaces)
+			board[tc.space] = 1 * sign
+			board[24] = 14 * sign
+
+			g := newTestGame(VariantTabula, board, player, 1, 2)
+			g.Player1.Entered, g.Player2.Entered = true, true
+			if secondHalf := g.SecondHalf(player, false); secondHalf != tc.expected {
+				t.Errorf("player %d: expected SecondHalf to return %v with a checker on space %d, got %v", player, tc.expected, tc.space, secondHalf)
+			}
+		}
+	}
+}
+
+func TestMoveTreeLocal(t *testing.T) {
+	board := make([]int8, BoardSpaces)
+	board[1], board[24] = -2, 2
+
+	g := newTestGame(VariantBackgammon, board, 2, 6, 5)
+	tree := g.MoveTree(true)
+	for _, first := range []string{"24/18", "24/19"} {
+		continuations, ok := tree[first]
+		if !ok {
+			t.Fatalf("expected local move tree to contain %s, got %v", first, tree)
+		}
+		for _, moves := range continuations {
+			for _, move := range moves {
+				if move[0] != 24 && move[0] != 19 && move[0] != 18 {
+					t.Errorf("expected local continuation of %s, got %s", first, FormatMoves(moves))
+				}
+			}
+		}
+	}
+	for first := range g.MoveTree(false) {
+		if first != "1/7" && first != "1/6" {
+			t.Errorf("expected absolute first move, got %s", first)
+		}
+	}
+}
+
+func TestUseDiceRollBearOff(t *testing.T) {
+	for _, tc := range []struct {
+		variant int8
+		from    int8
+		to      int8
+	}{
+		{variant: VariantBackgammon, from: 3, to: SpaceHomePlayer},
+		{variant: VariantBackgammon, from: 22, to: SpaceHomeOpponent},
+		{variant: VariantTabula, from: 22, to: SpaceHomePlayer},
+	} {
+		g := NewGame(tc.variant)
+		remaining, used := g.useDiceRoll([]int8{5, 6}, tc.from, tc.to)
+		if used != 6 || len(remaining) != 1 || remaining[0] != 5 {
+			t.Errorf("variant %d: %d/%d: expected the highest die to be used, got %d with %v remaining", tc.variant, tc.from, tc.to, used, remaining)
+		}
+		remaining, used = g.useDiceRoll([]int8{3, 6}, tc.from, tc.to)
+		if used != 3 || len(remaining) != 1 || remaining[0] != 6 {
+			t.Errorf("variant %d: %d/%d: expected the exact die to be used, got %d with %v remaining", tc.variant, tc.from, tc.to, used, remaining)
+		}
+		remaining, used = g.useDiceRoll([]int8{1, 2}, tc.from, tc.to)
+		if used != 0 || len(remaining) != 2 {
+			t.Errorf("variant %d: %d/%d: expected no die to be used, got %d with %v remaining", tc.variant, tc.from, tc.to, used, remaining)
+		}
+	}
+
+	board := make([]int8, BoardSpaces)
+	board[3], board[SpaceHomePlayer] = 1, 14
+	board[24], board[SpaceHomeOpponent] = -2, -13
+	g := newTestGame(VariantBackgammon, board, 1, 6, 5)
+	if moves := formatSorted(g.LegalMoves(false)); moves != "3/off" {
+		t.Fatalf("expected the last checker to be borne off, got %s", moves)
+	} else if ok, _ := g.AddMoves([][]int8{{3, SpaceHomePlayer}}, false); !ok {
+		t.Fatal("failed to bear off the last checker")
+	} else if g.Board[3] != 0 || g.Board[SpaceHomePlayer] != 15 {
+		t.Fatalf("expected all checkers to be borne off, got %d on the 3 point and %d borne off", g.Board[3], g.Board[SpaceHomePlayer])
+	} else if rolls := g.DiceRolls(); len(rolls) != 1 || rolls[0] != 5 {
+		t.Fatalf("expected the 6 to be used to bear off the last checker, got %v remaining", rolls)
+	}
+}
+
+func TestStakeAfterBearingOff(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		board    map[int8]int8
+		winType  int8
+		noCube   bool
+		expected int8
+	}{
+		{name: "single", board: map[int8]int8{SpaceHomeOpponent: -1, 20: -14}, winType: 1, expected: 4},
+		{name: "gammon", board: map[int8]int8{20: -15}, winType: 2, expected: 8},
+		{name: "backgammon", board: map[int8]int8{2: -1, 20: -14}, winType: 3, expected: 12},
+		{name: "backgammon from bar", board: map[int8]int8{SpaceBarOpponent: -1, 20: -14}, winType: 3, expected: 12},
+		{name: "cubeless backgammon", board: map[int8]int8{2: -1, 20: -14}, winType: 3, noCube: true, expected: 3},
+	} {
+		board := make([]int8, BoardSpaces)
+		board[3], board[SpaceHomePlayer] = 1, 14
+		for space, checkers := range tc.board {
+			board[space] = checkers
+		}
+		g := newTestGame(VariantBackgammon, board, 1, 6, 5)
+		g.DoubleValue = 4
+		g.NoCube = tc.noCube
+		if ok, _ := g.AddMoves([][]int8{{3, SpaceHomePlayer}}, false); !ok {
+			t.Errorf("%s: failed to bear off the last checker", tc.name)
+			continue
+		} else if g.Winner != 1 {
+			t.Errorf("%s: expected player 1 to win, got %d", tc.name, g.Winner)
+			continue
+		}
+		if winType := g.WinType(); winType != tc.winType {
+			t.Errorf("%s: expected win type %d, got %d", tc.name, tc.winType, winType)
+		}
+		if stake := g.Stake(); stake != tc.expected {
+			t.Errorf("%s: expected stake %d, got %d", tc.name, tc.expected, stake)
+		}
+	}
+}
+
+func TestMirrorBoardLegalMoves(t *testing.T) {
+	positions := map[int8][][]int8{
+		VariantBackgammon: {
+			NewBoard(VariantBackgammon),
+			{4, -2, 0, 0, 0, 0, 4, 0, 2, 0, 0, 0, -4, 3, 0, 0, 0, -3, 0, -4, 0, 0, 0, 0, 2, 0, 0, -2, 0, 0, 0, 0, 0, 0, 0},
+			{4, 0, 2, 3, 0, 3, 3, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, -2, -3, 0, -2, -4, -2, 0, -2, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+		},
+		VariantAceyDeucey: {
+			NewBoard(VariantAceyDeucey),
+			{8, 2, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 3, 2, 0, 0, -2, 0, 0, -3, 0, -10, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+		},
+	}
+	for variant, boards := range positions {
+		for i, board := range boards {
+			for roll1 := int8(1); roll1 <= 6; roll1++ {
+				for roll2 := roll1; roll2 <= 6; roll2++ {
+					g := newTestGame(variant, board, 1, roll1, roll2)
+					g.Player1.Entered, g.Player2.Entered = true, true
+					mirrored := newTestGame(variant, MirrorBoard(board, variant), 2, roll1, roll2)
+					mirrored.Player1.Entered, mirrored.Player2.Entered = true, true
+
+					expected := formatSorted(FlipMoves(g.LegalMoves(false), 2, variant))
+					if moves := formatSorted(mirrored.LegalMoves(false)); moves != expected {
+						t.Errorf("variant %d: position %d: roll %d-%d: expected mirrored legal moves %s, got %s", variant, i, roll1, roll2, expected, moves)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestHomeSize(t *testing.T) {
+	testCases := []struct {
+		name       string
+		board      map[int8]int8
+		roll1      int8
+		roll2      int8
+		mayBearOff bool
+		expected   string
+	}{
+		{
+			name:       "outside home board",
+			board:      map[int8]int8{5: 1, 3: 1, 2: 1},
+			roll1:      2,
+			roll2:      1,
+			mayBearOff: false,
+			expected:   "5/4 5/3 3/2 3/1 2/1",
+		},
+		{
+			name:       "within home board",
+			board:      map[int8]int8{3: 1, 2: 1},
+			roll1:      6,
+			roll2:      5,
+			mayBearOff: true,
+			expected:   "3/off 2/off",
+		},
+	}
+	for _, tc := range testCases {
+		for _, player := range []int8{1, 2} {
+			board := playerBoard(player, VariantBackgammon, tc.board)
+			g := newTestGame(VariantBackgammon, board, player, tc.roll1, tc.roll2)
+			g.HomeSize = 3
+			if mayBearOff := g.MayBearOff(player, false); mayBearOff != tc.mayBearOff {
+				t.Errorf("%s: player %d: expected MayBearOff to return %v, got %v", tc.name, player, tc.mayBearOff, mayBearOff)
+			}
+			if moves := formatSorted(g.LegalMovesForPlayer(player, false)); moves != tc.expected {
+				t.Errorf("%s: player %d: expected legal moves %s, got %s", tc.name, player, tc.expected, moves)
+			}
+		}
+	}
+}
+
+func TestOffCount(t *testing.T) {
+	g := NewGame(VariantBackgammon)
+	for _, player := range []int8{1, 2} {
+		if off, remaining := g.OffCount(player), g.CheckersToBearOff(player); off != 0 || remaining != 15 {
+			t.Errorf("player %d: expected no checkers borne off at the start of the game, got %d borne off and %d remaining", player, off, remaining)
+		}
+	}
+
+	board := make([]int8, BoardSpaces)
+	board[SpaceHomePlayer], board[3] = 4, 11
+	board[SpaceHomeOpponent], board[22] = -7, -8
+	g = newTestGame(VariantBackgammon, board, 1, 0, 0)
+	if off, remaining := g.OffCount(1), g.CheckersToBearOff(1); off != 4 || remaining != 11 {
+		t.Errorf("player 1: expected 4 checkers borne off and 11 remaining, got %d and %d", off, remaining)
+	}
+	if off, remaining := g.OffCount(2), g.CheckersToBearOff(2); off != 7 || remaining != 8 {
+		t.Errorf("player 2: expected 7 checkers borne off and 8 remaining, got %d and %d", off, remaining)
+	}
+
+	// Checkers waiting to enter in tabula have not been borne off.
+	g = NewGame(VariantTabula)
+	for _, player := range []int8{1, 2} {
+		if off, remaining := g.OffCount(player), g.CheckersToBearOff(player); off != 0 || remaining != 15 {
+			t.Errorf("tabula: player %d: expected no checkers borne off before entering, got %d borne off and %d remaining", player, off, remaining)
+		}
+	}
+	board = make([]int8, BoardSpaces)
+	board[SpaceHomePlayer], board[20] = 3, 12
+	board[SpaceHomeOpponent] = -15
+	g = newTestGame(VariantTabula, board, 1, 0, 0)
+	g.Player1.Entered = true
+	if off, remaining := g.OffCount(1), g.CheckersToBearOff(1); off != 3 || remaining != 12 {
+		t.Errorf("tabula: player 1: expected 3 checkers borne off and 12 remaining, got %d and %d", off, remaining)
+	}
+	if off, remaining := g.OffCount(2), g.CheckersToBearOff(2); off != 0 || remaining != 15 {
+		t.Errorf("tabula: player 2: expected no checkers borne off before entering, got %d and %d", off, remaining)
+	}
+}
+
+func TestOccupiedSpaces(t *testing.T) {
+	testCases := []struct {
+		name     string
+		variant  int8
+		board    map[int8]int8
+		expected [2]map[int8]int8
+	}{
+		{
+			name:    "backgammon",
+			variant: VariantBackgammon,
+			board: map[int8]int8{
+				SpaceHomePlayer: 2, SpaceBarPlayer: 1, 6: 12,
+				SpaceHomeOpponent: -3, SpaceBarOpponent: -1, 19: -11,
+			},
+			expected: [2]map[int8]int8{
+				{SpaceHomePlayer: 2, SpaceBarPlayer: 1, 6: 12},
+				{SpaceHomeOpponent: 3, SpaceBarOpponent: 1, 19: 11},
+			},
+		},
+		{
+			name:    "tabula",
+			variant: VariantTabula,
+			board: map[int8]int8{
+				SpaceHomePlayer: 10, SpaceBarPlayer: 1, 22: 4,
+				SpaceHomeOpponent: -13, SpaceBarOpponent: -1, 20: -1,
+			},
+			expected: [2]map[int8]int8{
+				{SpaceHomePlayer: 10, SpaceBarPlayer: 1, 22: 4},
+				{SpaceHomeOpponent: 13, SpaceBarOpponent: 1, 20: 1},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		board := make([]int8, BoardSpaces)
+		for space, checkers := range tc.board {
+			board[space] = checkers
+		}
+		g := newTestGame(tc.variant, board, 1, 0, 0)
+		for _, player := range []int8{1, 2} {
+			expected := tc.expected[player-1]
+			var expectedSpaces []int8
+			for space := int8(0); space < BoardSpaces; space++ {
+				if expected[space] != 0 {
+					expectedSpaces = append(expectedSpaces, space)
+				}
+			}
+			if spaces := g.OccupiedSpaces(player); !reflect.DeepEqual(spaces, expectedSpaces) {
+				t.Errorf("%s: player %d: expected occupied spaces %v, got %v", tc.name, player, expectedSpaces, spaces)
+			}
+			if checkers := g.CheckerMap(player); !reflect.DeepEqual(checkers, expected) {
+				t.Errorf("%s: player %d: expected checkers %v, got %v", tc.name, player, expected, checkers)
+			}
+		}
+	}
+}
+
+func TestAddMovesOrder(t *testing.T) {
+	board := make([]int8, BoardSpaces)
+	board[13], board[SpaceHomePlayer] = 1, 14
+	board[1] = -15
+
+	g := newTestGame(VariantBackgammon, board, 1, 6, 5)
+	if ok, _ := g.AddMoves([][]int8{{8, 2}, {13, 8}}, false); !ok {
+		t.Fatal("expected moves submitted out of order to be accepted")
+	} else if g.Board[2] != 1 || g.Board[13] != 0 {
+		t.Fatalf("expected the checker to be moved from 13 to 2, got %d on 13 and %d on 2", g.Board[13], g.Board[2])
+	}
+
+	g = newTestGame(VariantBackgammon, board, 1, 6, 5)
+	if ok, _ := g.AddMoves([][]int8{{8, 2}}, false); ok {
+		t.Fatal("expected a single move from an empty space to be refused")
+	} else if ok, _ = g.AddMoves([][]int8{{13, 8}}, false); !ok {
+		t.Fatal("expected a single legal move to be accepted")
+	}
+}
+
+func TestMoveDiceAssignment(t *testing.T) {
+	format := func(assignment []MoveDie) string {
+		var s string
+		for _, a := range assignment {
+			s += string(FormatMoves([][]int8{a.Move})) + ":" + string(FormatSpace(a.Die)) + " "
+		}
+		return s
+	}
+	testCases := []struct {
+		name     string
+		roll1    int8
+		roll2    int8
+		moves    [][]int8
+		expected string
+		local    string
+	}{
+		{
+			name:     "combined move",
+			roll1:    6,
+			roll2:    5,
+			moves:    [][]int8{{1, 12}},
+			expected: "1/7:6 7/12:5 ",
+			local:    "24/18:6 18/13:5 ",
+		},
+		{
+			name:     "doublet",
+			roll1:    2,
+			roll2:    2,
+			moves:    [][]int8{{1, 5}, {12, 14}, {12, 14}},
+			expected: "1/3:2 3/5:2 12/14:2 12/14:2 ",
+			local:    "24/22:2 22/20:2 13/11:2 13/11:2 ",
+		},
+	}
+	for _, tc := range testCases {
+		g := newTestGame(VariantBackgammon, NewBoard(VariantBackgammon), 2, tc.roll1, tc.roll2)
+		for _, move := range tc.moves {
+			if !g.AddLocalMove(move) {
+				t.Fatalf("%s: failed to add move %s", tc.name, FormatMoves([][]int8{move}))
+			}
+		}
+		if assignment := format(g.MoveDiceAssignment(false)); assignment != tc.expected {
+			t.Errorf("%s: expected assignment %s, got %s", tc.name, tc.expected, assignment)
+		}
+		if assignment := format(g.MoveDiceAssignment(true)); assignment != tc.local {
+			t.Errorf("%s: expected local assignment %s, got %s", tc.name, tc.local, assignment)
+		}
+	}
+}
@@ -1,9 +1,5 @@
 package bgammon
 
-import (
-	"log"
-)
-
 type GameState struct {
 	*Game
 	PlayerNumber int8
@@ -60,13 +56,8 @@ func (g *GameState) SpaceAt(x int8, y int8) int8 {
 
 // Pips returns the pip count for the specified player.
 func (g *GameState) Pips(player int8) int {
-	var pips int
+	pips := g.BarPips(player)
 	var spaceValue int
-	if player == 1 {
-		pips += int(PlayerCheckers(g.Board[SpaceBarPlayer], player)) * 25
-	} else {
-		pips += int(PlayerCheckers(g.Board[SpaceBarOpponent], player)) * 25
-	}
 	if g.Variant != VariantBackgammon {
 		if player == 1 && !g.Player1.Entered {
 			pips += int(PlayerCheckers(g.Board[SpaceHomePlayer], player)) * 25
@@ -87,7 +78,7 @@ func (g *GameState) Pips(player int8) int {
 
 // MayDouble returns whether the player may send the 'double' command.
 func (g *GameState) MayDouble() bool {
-	if g.Spectating || g.Winner != 0 || g.Variant != VariantBackgammon {
+	if g.Spectating || g.Winner != 0 || g.NoCube || g.Variant != VariantBackgammon || g.Crawford {
 		return false
 	}
 	return g.Points != 1 && g.Turn != 0 && g.Turn == g.PlayerNumber && g.Roll1 == 0 && !g.DoubleOffered && (g.DoublePlayer == 0 || g.DoublePlayer == g.PlayerNumber)
@@ -95,25 +86,10 @@ func (g *GameState) MayDouble() bool {
 
 // MayRoll returns whether the player may send the 'roll' command.
 func (g *GameState) MayRoll() bool {
-	if g.Spectating || g.Winner != 0 || g.DoubleOffered {
-		return false
-	}
-	switch g.Turn {
-	case 0:
-		if g.PlayerNumber == 1 {
-			return g.Player2.Name != "" && (g.Roll1 == 0 || (g.Roll1 == g.Roll2))
-		} else if g.PlayerNumber == 2 {
-			return g.Player1.Name != "" && (g.Roll2 == 0 || (g.Roll1 == g.Roll2))
-		}
-		return false
-	case 1:
-		return g.PlayerNumber == g.Turn && g.Player2.Name != "" && g.Roll1 == 0
-	case 2:
-		return g.PlayerNumber == g.Turn && g.Player1.Name != "" && g.Roll1 == 0
-	default:
-		log.Panicf("unknown turn %d", g.Turn)
+	if g.Spectating {
 		return false
 	}
+	return g.Game.MayRoll(g.PlayerNumber)
 }
 
 // MayChooseRoll returns whether the player may send the 'ok' command, supplying
@@ -129,7 +105,7 @@ func (g *GameState) MayOK() bool {
 	} else if g.Turn != 0 && g.Turn != g.PlayerNumber && g.PlayerNumber != g.DoublePlayer && g.DoubleOffered {
 		return true
 	}
-	return g.Turn != 0 && g.Turn == g.PlayerNumber && g.Roll1 != 0 && len(g.Available) == 0
+	return g.Turn != 0 && g.Turn == g.PlayerNumber && g.Roll1 != 0 && (len(g.Available) == 0 || g.AllowUnusedDice)
 }
 
 // MayDecline returns whether the player may send the 'resign' command to
@@ -143,7 +119,7 @@ func (g *GameState) MayDecline() bool {
 
 // MayReset returns whether the player may send the 'reset' command.
 func (g *GameState) MayReset() bool {
-	if g.Spectating || g.Winner != 0 {
+	if g.Spectating || g.Winner != 0 || g.NoUndo {
 		return false
 	}
 	return g.Turn != 0 && g.Turn == g.PlayerNumber && len(g.Moves) > 0
@@ -0,0 +1,149 @@
+package server
+
+import (
+	"sync"
+	"time"
+
+	"code.rocket9labs.com/tslocum/bgammon"
+)
+
+// botInterval is the delay between actions taken by bots in demo matches.
+const botInterval = 2 * time.Second
+
+var _ bgammon.Client = &botClient{}
+
+// botClient is a client controlled by the server. Bots receive events like any
+// other JSON client and act upon the most recent board state they received.
+type botClient struct {
+	policy     bgammon.MovePolicy
+	state      *bgammon.GameState
+	stateLock  sync.Mutex // Guards state and terminated, which are accessed by the server and by runBot.
+	terminated bool
+}
+
+func newBotClient(policy bgammon.MovePolicy) *botClient {
+	return &botClient{
+		policy: policy,
+	}
+}
+
+// botPolicy returns the move policy used by bots of the provided level.
+func botPolicy(level int) bgammon.MovePolicy {
+	switch level {
+	case 0:
+		return bgammon.RandomPolicy{}
+	case 1:
+		return bgammon.HeuristicPolicy{}
+	default:
+		return bgammon.BestBearOffPolicy{}
+	}
+}
+
+func (c *botClient) HandleReadWrite() {
+}
+
+func (c *botClient) Write(message []byte) {
+	ev, err := bgammon.DecodeEvent(message)
+	if err != nil {
+		return
+	}
+	board, ok := ev.(*bgammon.EventBoard)
+	if !ok {
+		return
+	}
+	c.stateLock.Lock()
+	c.state = &board.GameState
+	c.stateLock.Unlock()
+}
+
+func (c *botClient) Terminate(reason string) {
+	c.stateLock.Lock()
+	c.terminated = true
+	c.stateLock.Unlock()
+}
+
+func (c *botClient) Terminated() bool {
+	c.stateLock.Lock()
+	defer c.stateLock.Unlock()
+	return c.terminated
+}
+
+// nextCommand returns the command the bot should send based on the most recent
+// board state it received, or nil when the bot should wait.
+func (c *botClient) nextCommand() []byte {
+	c.stateLock.Lock()
+	gs := c.state
+	c.stateLock.Unlock()
+	switch {
+	case gs == nil || gs.Spectating:
+		return nil
+	case gs.Winner != 0:
+		return []byte(bgammon.CommandRematch)
+	case gs.MayRoll():
+		return []byte(bgammon.CommandRoll)
+	case gs.Turn != gs.PlayerNumber || gs.Roll1 == 0 || gs.DoubleOffered:
+		return nil
+	}
+	moves := bgammon.BotMove(gs.Game, false, c.policy)
+	if len(moves) == 0 || len(gs.Available) == 0 {
+		return []byte(bgammon.CommandOk)
+	}
+	return append([]byte(bgammon.CommandMove+" "), bgammon.FormatMoves(moves)...)
+}
+
+// newBot returns a server client controlled by a bot of the provided level.
+func (s *server) newBot(name string, level int) *serverClient {
+	now := time.Now().Unix()
+	return &serverClient{
+		id:        <-s.newClientIDs,
+		json:      true,
+		name:      []byte(name),
+		language:  "bgammon-en",
+		connected: now,
+		active:    now,
+		Client:    newBotClient(botPolicy(level)),
+	}
+}
+
+// runBot sends the commands chosen by the provided bot until it is terminated.
+func (s *server) runBot(client *serverClient) {
+	bot := client.Client.(*botClient)
+	t := time.NewTicker(botInterval)
+	defer t.Stop()
+	for range t.C {
+		if bot.Terminated() {
+			return
+		}
+		command := bot.nextCommand()
+		if command == nil {
+			continue
+		}
+		s.commands <- serverCommand{
+			client:  client,
+			command: command,
+		}
+	}
+}
+
+// startDemo creates a match between two bots of the provided levels which is
+// spectated by the provided client. The match is removed once no spectators
+// remain.
+func (s *server) startDemo(level1 int, level2 int, spectator *serverClient) *serverGame {
+	bot1 := s.newBot("bot_demo1", level1)
+	bot2 := s.newBot("bot_demo2", level2)
+
+	g := newServerGame(<-s.newGameIDs, bgammon.VariantBackgammon)
+	g.name = []byte("Bot demo")
+	g.addClient(bot1)
+	g.addClient(bot2)
+	g.demo = true
+	g.addClient(spectator)
+
+	s.gamesLock.Lock()
+	s.games = append(s.games, g)
+	s.gamesLock.Unlock()
+
+	go s.runBot(bot1)
+	go s.runBot(bot2)
+	return g
+}
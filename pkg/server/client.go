@@ -78,8 +78,11 @@ type serverClient struct {
 	connected    int64
 	active       int64
 	lastPing     int64
+	lastRoll     time.Time
 	commands     chan []byte
 	autoplay     bool
+	color        string
+	flip         bool
 	playerNumber int8
 	terminating  bool
 	bgammon.Client
@@ -131,6 +134,14 @@ func (c *serverClient) sendEvent(e interface{}) {
 			ev.Type = bgammon.EventTypeReplay
 		case *bgammon.EventHistory:
 			ev.Type = bgammon.EventTypeHistory
+		case *bgammon.EventDrawOffer:
+			ev.Type = bgammon.EventTypeDrawOffer
+		case *bgammon.EventDraw:
+			ev.Type = bgammon.EventTypeDraw
+		case *bgammon.EventPaused:
+			ev.Type = bgammon.EventTypePaused
+		case *bgammon.EventResumed:
+			ev.Type = bgammon.EventTypeResumed
 		default:
 			log.Panicf("unknown event type %+v", ev)
 		}
@@ -193,6 +204,14 @@ func (c *serverClient) sendEvent(e interface{}) {
 		} else {
 			c.Write([]byte(fmt.Sprintf("win %s wins!", ev.Player)))
 		}
+	case *bgammon.EventDrawOffer:
+		c.Write([]byte(fmt.Sprintf("drawoffer %s offers a draw.", ev.Player)))
+	case *bgammon.EventDraw:
+		c.Write([]byte("draw The game was drawn."))
+	case *bgammon.EventPaused:
+		c.Write([]byte("paused The match has been paused."))
+	case *bgammon.EventResumed:
+		c.Write([]byte("resumed The match has been resumed."))
 	default:
 		log.Printf("warning: skipped sending unknown event to non-json client: %+v", ev)
 	}
@@ -73,7 +73,9 @@ CREATE TABLE game (
 	points   integer NOT NULL,
 	winner   integer NOT NULL,
 	wintype  integer NOT NULL,
-	replay   TEXT NOT NULL DEFAULT ''
+	replay   TEXT NOT NULL DEFAULT '',
+	private  boolean NOT NULL DEFAULT false,
+	turns    integer NOT NULL DEFAULT 0
 );
 `
 
@@ -126,6 +128,13 @@ func initDB() {
 	if err != nil {
 		log.Fatal(err)
 	} else if result > 0 {
+		// Add columns introduced after the database was initialized.
+		for _, column := range []string{"private boolean NOT NULL DEFAULT false", "turns integer NOT NULL DEFAULT 0"} {
+			_, err = tx.Exec(context.Background(), "ALTER TABLE game ADD COLUMN IF NOT EXISTS "+column)
+			if err != nil {
+				log.Fatalf("failed to update database schema: %s", err)
+			}
+		}
 		return // Database has been initialized.
 	}
 
@@ -515,12 +524,26 @@ func setAccountSetting(id int, name string, value int) error {
 	return err
 }
 
-func recordGameResult(g *serverGame, winType int8, replay [][]byte) error {
+// replayTurns returns the number of turns recorded in the provided replay.
+func replayTurns(replay [][]byte) int {
+	var turns int
+	for _, line := range replay {
+		fields := bytes.Fields(line)
+		if len(fields) >= 2 && len(fields[1]) == 1 && fields[1][0] == bgammon.ReplayEventRoll {
+			turns++
+		}
+	}
+	return turns
+}
+
+// recordGameResult records the result of the current game and returns the ID
+// of the recorded game. 0 is returned when the game was not recorded.
+func recordGameResult(g *serverGame, winType int8, replay [][]byte) (int, error) {
 	dbLock.Lock()
 	defer dbLock.Unlock()
 
 	if db == nil || g.Started.IsZero() || g.Winner == 0 || len(g.replay) == 0 {
-		return nil
+		return 0, nil
 	}
 
 	ended := g.Ended
@@ -530,28 +553,48 @@ func recordGameResult(g *serverGame, winType int8, replay [][]byte) error {
 
 	tx, err := begin()
 	if err != nil {
-		return err
+		return 0, err
 	}
 	defer tx.Commit(context.Background())
 
-	_, err = tx.Exec(context.Background(), "INSERT INTO game (variant, started, ended, player1, account1, player2, account2, points, winner, wintype, replay) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)", g.Variant, g.Started.Unix(), ended.Unix(), g.allowed1, g.account1, g.allowed2, g.account2, g.Points, g.Winner, winType, bytes.Join(replay, []byte("\n")))
+	var id int
+	err = tx.QueryRow(context.Background(), "INSERT INTO game (variant, started, ended, player1, account1, player2, account2, points, winner, wintype, replay, private, turns) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING id", g.Variant, g.Started.Unix(), ended.Unix(), g.allowed1, g.account1, g.allowed2, g.account2, g.Points, g.Winner, winType, bytes.Join(replay, []byte("\n")), len(g.password) != 0, replayTurns(replay)).Scan(&id)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	if g.account1 != 0 {
 		_, err = tx.Exec(context.Background(), "UPDATE account SET active = $1 WHERE id = $2", time.Now().Unix(), g.account1)
 		if err != nil {
-			return err
+			return 0, err
 		}
 	}
 	if g.account2 != 0 {
 		_, err = tx.Exec(context.Background(), "UPDATE account SET active = $1 WHERE id = $2", time.Now().Unix(), g.account2)
 		if err != nil {
-			return err
+			return 0, err
 		}
 	}
-	return nil
+	return id, nil
+}
+
+// deleteGameResult removes a game recorded by recordGameResult.
+func deleteGameResult(id int) error {
+	dbLock.Lock()
+	defer dbLock.Unlock()
+
+	if db == nil || id <= 0 {
+		return nil
+	}
+
+	tx, err := begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Commit(context.Background())
+
+	_, err = tx.Exec(context.Background(), "DELETE FROM game WHERE id = $1", id)
+	return err
 }
 
 func recordMatchResult(g *serverGame, matchType int) error {
@@ -618,6 +661,8 @@ func recordMatchResult(g *serverGame, matchType int) error {
 	return nil
 }
 
+// matchInfo returns the replay of the provided game for download. Replays of
+// private matches are never returned, as downloads are not authenticated.
 func matchInfo(id int) (timestamp int64, player1 string, player2 string, replay []byte, err error) {
 	dbLock.Lock()
 	defer dbLock.Unlock()
@@ -634,14 +679,16 @@ func matchInfo(id int) (timestamp int64, player1 string, player2 string, replay
 	}
 	defer tx.Commit(context.Background())
 
-	err = tx.QueryRow(context.Background(), "SELECT started, player1, player2, replay FROM game WHERE id = $1 AND replay != ''", id).Scan(&timestamp, &player1, &player2, &replay)
+	err = tx.QueryRow(context.Background(), "SELECT started, player1, player2, replay FROM game WHERE id = $1 AND replay != '' AND private = false", id).Scan(&timestamp, &player1, &player2, &replay)
 	if err != nil {
 		return 0, "", "", nil, err
 	}
 	return timestamp, player1, player2, replay, nil
 }
 
-func replayByID(id int) ([]byte, error) {
+// replayByID returns the replay of the provided game. Replays of private
+// matches are only returned to the players of the match.
+func replayByID(id int, accountID int) ([]byte, error) {
 	dbLock.Lock()
 	defer dbLock.Unlock()
 
@@ -658,9 +705,13 @@ func replayByID(id int) ([]byte, error) {
 	defer tx.Commit(context.Background())
 
 	var replay []byte
-	err = tx.QueryRow(context.Background(), "SELECT replay FROM game WHERE id = $1", id).Scan(&replay)
+	var private bool
+	var account1, account2 int
+	err = tx.QueryRow(context.Background(), "SELECT replay, private, account1, account2 FROM game WHERE id = $1", id).Scan(&replay, &private, &account1, &account2)
 	if err != nil {
 		return nil, nil
+	} else if private && (accountID == 0 || (accountID != account1 && accountID != account2)) {
+		return nil, fmt.Errorf("replay of private match is not available")
 	}
 	return replay, nil
 }
@@ -784,6 +835,23 @@ func getLeaderboard(matchType int, variant int8, multiPoint bool) (*leaderboardR
 	return result, nil
 }
 
+// longestGames returns the game with the most turns and the game which lasted
+// the longest.
+func longestGames(tx pgx.Tx) (byTurns *longestGameEntry, byDuration *longestGameEntry, err error) {
+	byTurns, byDuration = &longestGameEntry{}, &longestGameEntry{}
+	err = tx.QueryRow(context.Background(), "SELECT id, player1, player2, turns, ended - started FROM game ORDER BY turns DESC, id ASC LIMIT 1").Scan(&byTurns.ID, &byTurns.Player1, &byTurns.Player2, &byTurns.Turns, &byTurns.Duration)
+	if err == pgx.ErrNoRows {
+		return nil, nil, nil
+	} else if err != nil {
+		return nil, nil, err
+	}
+	err = tx.QueryRow(context.Background(), "SELECT id, player1, player2, turns, ended - started FROM game ORDER BY ended - started DESC, id ASC LIMIT 1").Scan(&byDuration.ID, &byDuration.Player1, &byDuration.Player2, &byDuration.Turns, &byDuration.Duration)
+	if err != nil {
+		return nil, nil, err
+	}
+	return byTurns, byDuration, nil
+}
+
 func dailyStats(tz *time.Location) (*serverStatsResult, error) {
 	dbLock.Lock()
 	defer dbLock.Unlock()
@@ -810,6 +878,11 @@ func dailyStats(tz *time.Location) (*serverStatsResult, error) {
 	}
 
 	result := &serverStatsResult{}
+	result.LongestGameTurns, result.LongestGameDuration, err = longestGames(tx)
+	if err != nil {
+		return nil, err
+	}
+
 	earliest := midnight(time.Unix(earliestGame, 0).In(tz))
 	rangeStart, rangeEnd := earliest.Unix(), earliest.AddDate(0, 0, 1).Unix()
 	var games, accounts int
@@ -957,6 +1030,11 @@ func cumulativeStats(tz *time.Location) (*serverStatsResult, error) {
 	}
 
 	result := &serverStatsResult{}
+	result.LongestGameTurns, result.LongestGameDuration, err = longestGames(tx)
+	if err != nil {
+		return nil, err
+	}
+
 	earliest := midnight(time.Unix(earliestGame, 0).In(tz))
 	rangeStart, rangeEnd := earliest.Unix(), earliest.AddDate(0, 0, 1).Unix()
 	var games, accounts int
@@ -1007,6 +1085,83 @@ func cumulativeStats(tz *time.Location) (*serverStatsResult, error) {
 	return result, nil
 }
 
+// openingStats returns how often the player making the opening move went on
+// to win the game, grouped by the opening roll and then by the moves played.
+// Moves are numbered from the perspective of the player making the move.
+func openingStats(variant int8) (map[string]map[string]float64, error) {
+	dbLock.Lock()
+	defer dbLock.Unlock()
+
+	stats := make(map[string]map[string]float64)
+	if db == nil {
+		return stats, nil
+	}
+
+	tx, err := begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Commit(context.Background())
+
+	type openingRecord struct {
+		games int
+		wins  int
+	}
+	records := make(map[string]map[string]*openingRecord)
+	var replay []byte
+	var winner int8
+	rows, err := tx.Query(context.Background(), "SELECT replay, winner FROM game WHERE variant = $1 AND replay != ''", variant)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		err = rows.Scan(&replay, &winner)
+		if err != nil {
+			return nil, err
+		}
+		r, err := bgammon.DecodeReplay(replay)
+		if err != nil || len(r.Games) == 0 {
+			continue
+		}
+		var opening *bgammon.ReplayEvent
+		for _, ev := range r.Games[0].Events {
+			if ev.Type == bgammon.ReplayEventRoll {
+				opening = ev
+				break
+			}
+		}
+		if opening == nil || len(opening.Moves) == 0 {
+			continue
+		}
+		roll1, roll2 := opening.Roll[0], opening.Roll[1]
+		if roll2 > roll1 {
+			roll1, roll2 = roll2, roll1
+		}
+		moves := bgammon.FlipMoves(opening.Moves, opening.Player, variant)
+		bgammon.SortMoves(moves)
+		rollKey, movesKey := fmt.Sprintf("%d-%d", roll1, roll2), string(bgammon.FormatMoves(moves))
+		if records[rollKey] == nil {
+			records[rollKey] = make(map[string]*openingRecord)
+		}
+		record := records[rollKey][movesKey]
+		if record == nil {
+			record = &openingRecord{}
+			records[rollKey][movesKey] = record
+		}
+		record.games++
+		if winner == opening.Player {
+			record.wins++
+		}
+	}
+	for rollKey, plays := range records {
+		stats[rollKey] = make(map[string]float64)
+		for movesKey, record := range plays {
+			stats[rollKey][movesKey] = float64(record.wins) / float64(record.games)
+		}
+	}
+	return stats, nil
+}
+
 func botStats(name string, tz *time.Location) (*botStatsResult, error) {
 	dbLock.Lock()
 	defer dbLock.Unlock()
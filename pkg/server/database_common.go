@@ -23,8 +23,18 @@ type serverStatsEntry struct {
 	Accounts int
 }
 
+type longestGameEntry struct {
+	ID       int
+	Player1  string
+	Player2  string
+	Turns    int
+	Duration int64 // Seconds.
+}
+
 type serverStatsResult struct {
-	History []*serverStatsEntry
+	History             []*serverStatsEntry
+	LongestGameTurns    *longestGameEntry // Game with the most turns.
+	LongestGameDuration *longestGameEntry // Game which lasted the longest.
 }
 
 type botStatsEntry struct {
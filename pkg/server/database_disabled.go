@@ -55,11 +55,15 @@ func matchInfo(id int) (timestamp int64, player1 string, player2 string, replay
 	return 0, "", "", nil, nil
 }
 
-func replayByID(id int) ([]byte, error) {
+func replayByID(id int, accountID int) ([]byte, error) {
 	return nil, nil
 }
 
-func recordGameResult(g *serverGame, winType int8, replay [][]byte) error {
+func recordGameResult(g *serverGame, winType int8, replay [][]byte) (int, error) {
+	return 0, nil
+}
+
+func deleteGameResult(id int) error {
 	return nil
 }
 
@@ -87,6 +91,10 @@ func cumulativeStats(tz *time.Location) (*serverStatsResult, error) {
 	return &serverStatsResult{}, nil
 }
 
+func openingStats(variant int8) (map[string]map[string]float64, error) {
+	return make(map[string]map[string]float64), nil
+}
+
 func botStats(name string, tz *time.Location) (*botStatsResult, error) {
 	return &botStatsResult{}, nil
 }
@@ -5,11 +5,17 @@ import (
 	"bytes"
 	"fmt"
 	"log"
+	"strconv"
 	"time"
 
 	"code.rocket9labs.com/tslocum/bgammon"
+	"code.rocket9labs.com/tslocum/gotext"
 )
 
+// chatHistory is the number of recent chat messages sent to spectators when
+// they join a match.
+const chatHistory = 20
+
 type serverGame struct {
 	id         int
 	created    int64
@@ -26,12 +32,75 @@ type serverGame struct {
 	inactive   int8
 	forefeit   int8
 	rematch    int8
+	draw       int8 // Player offering a draw.
 	rejoin1    bool
 	rejoin2    bool
+	restored   bool // Restored by an administrator for inspection. Restored matches may only be spectated.
+	demo       bool // Played between two bots. Demo matches may only be spectated.
 	replay     [][]byte
+	opening    [2]int8             // Predetermined opening roll of each player. Only the first opening roll is affected.
+	chat       []*bgammon.EventSay // Recent chat messages. Only recorded when chat is relayed to spectators.
+
+	paused           bool
+	pauseRequestedBy int8 // Player requesting to pause or resume the match.
+	pauseStarted     time.Time
+	pauseTotal       time.Duration // Total time the match has been paused, not including the current pause.
+
+	takeback        *bgammon.Game // State of the game at the start of the previous turn.
+	takebackReplay  int           // Length of the replay before the previous turn was recorded.
+	takebackRequest int8          // Player requesting to take back the previous turn.
+	turnReplay      int           // Length of the replay before the most recent turn was recorded.
+
+	declined        *bgammon.Game // State of the game before a double offer was declined.
+	declinedReplay  [][]byte      // Replay of the game before a double offer was declined.
+	declinedRequest int8          // Player requesting to undo declining a double offer.
+	declinedGame    int           // ID of the game recorded when the double offer was declined.
 	*bgammon.Game
 }
 
+// gameSnapshot is the state of a match as serialized by the dump command.
+type gameSnapshot struct {
+	ID       int
+	Name     string
+	Player1  string
+	Player2  string
+	Account1 int
+	Account2 int
+	Replay   []string
+	Game     *bgammon.Game
+}
+
+func (g *serverGame) snapshot() *gameSnapshot {
+	s := &gameSnapshot{
+		ID:       g.id,
+		Name:     string(g.name),
+		Player1:  string(g.allowed1),
+		Player2:  string(g.allowed2),
+		Account1: g.account1,
+		Account2: g.account2,
+		Replay:   make([]string, len(g.replay)),
+		Game:     g.Game,
+	}
+	for i := range g.replay {
+		s.Replay[i] = string(g.replay[i])
+	}
+	return s
+}
+
+func (g *serverGame) restore(s *gameSnapshot) {
+	g.name = []byte(s.Name)
+	if s.Player1 != "" {
+		g.allowed1, g.allowed2 = []byte(s.Player1), []byte(s.Player2)
+	}
+	g.account1, g.account2 = s.Account1, s.Account2
+	g.replay = make([][]byte, len(s.Replay))
+	for i := range s.Replay {
+		g.replay[i] = []byte(s.Replay[i])
+	}
+	g.Game = s.Game
+	g.restored = true
+}
+
 func newServerGame(id int, variant int8) *serverGame {
 	now := time.Now().Unix()
 	return &serverGame{
@@ -43,7 +112,7 @@ func newServerGame(id int, variant int8) *serverGame {
 }
 
 func (g *serverGame) playForcedMoves() bool {
-	if g.Winner != 0 || len(g.Moves) != 0 || g.client1 == nil || g.client2 == nil {
+	if g.Winner != 0 || len(g.Moves) != 0 || g.client1 == nil || g.client2 == nil || g.AllowUnusedDice {
 		return false
 	}
 	rolls := g.DiceRolls()
@@ -133,22 +202,75 @@ func (g *serverGame) playForcedMoves() bool {
 	return true
 }
 
+// passDancedTurn ends the current turn when the player is unable to enter a
+// checker from the bar. The roll is still recorded in the replay.
+func (g *serverGame) passDancedTurn() bool {
+	if g.Winner != 0 || g.Turn == 0 || g.client1 == nil || g.client2 == nil {
+		return false
+	}
+	client, playerName, opponent := g.client1, g.Player1.Name, int8(2)
+	if g.Turn == 2 {
+		client, playerName, opponent = g.client2, g.Player2.Name, 1
+	}
+	// Both players being closed out would otherwise pass turns indefinitely.
+	if !client.autoplay || !g.Danced(g.Turn) || g.ClosedOut(opponent) {
+		return false
+	}
+	if detailedPassNotices && g.Variant != bgammon.VariantTabula {
+		dance := g.DanceRolls(g.Turn)
+		g.eachClient(func(client *serverClient) {
+			client.sendNotice(fmt.Sprintf(gotext.GetD(client.language, "%s rolled %d-%d and is unable to enter from the bar (%d of 36 rolls fail to enter). Passing turn."), playerName, g.Roll1, g.Roll2, dance))
+		})
+	} else {
+		g.eachClient(func(client *serverClient) {
+			client.sendNotice(fmt.Sprintf(gotext.GetD(client.language, "%s is unable to enter from the bar. Passing turn."), playerName))
+		})
+	}
+	g.recordEvent()
+	g.nextTurn(false)
+	return true
+}
+
+// rollError returns the reason the provided client may not roll, or an empty
+// string when the client may roll.
+func (g *serverGame) rollError(client *serverClient) string {
+	player := client.playerNumber
+	switch {
+	case g.DoubleOffered:
+		return gotext.GetD(client.language, "You may not roll until the double offer is answered.")
+	case g.Turn == 0:
+		if (player == 1 && g.Roll1 != 0) || (player == 2 && g.Roll2 != 0) {
+			return gotext.GetD(client.language, "You have already rolled.")
+		}
+	case g.Turn != player:
+		return gotext.GetD(client.language, "It is not your turn to roll.")
+	case g.Roll1 != 0 || g.Roll2 != 0:
+		return gotext.GetD(client.language, "You have already rolled.")
+	}
+	return ""
+}
+
 func (g *serverGame) roll(player int8) bool {
-	if g.client1 == nil || g.client2 == nil || g.Winner != 0 {
+	if g.client1 == nil || g.client2 == nil || g.Winner != 0 || g.DoubleOffered {
 		return false
 	}
 
 	if g.Turn == 0 {
+		// Turns played and double offers declined during the previous game may
+		// not be taken back.
+		g.takeback, g.takebackRequest = nil, 0
+		g.declined, g.declinedReplay, g.declinedRequest, g.declinedGame = nil, nil, 0, 0
+
 		if player == 1 {
 			if g.Roll1 != 0 {
 				return false
 			}
-			g.Roll1 = int8(RandInt(6) + 1)
+			g.Roll1 = g.openingRoll(1)
 		} else {
 			if g.Roll2 != 0 {
 				return false
 			}
-			g.Roll2 = int8(RandInt(6) + 1)
+			g.Roll2 = g.openingRoll(2)
 		}
 
 		// Only allow the same players to rejoin the game.
@@ -180,6 +302,41 @@ func (g *serverGame) roll(player int8) bool {
 	return true
 }
 
+// openingRoll returns the opening roll of the provided player. A predetermined
+// opening roll is only used once, after which the dice are rolled normally.
+func (g *serverGame) openingRoll(player int8) int8 {
+	roll := g.opening[player-1]
+	if roll == 0 {
+		return int8(RandInt(6) + 1)
+	}
+	g.opening[player-1] = 0
+	return roll
+}
+
+// parseOpeningRoll parses an opening roll formatted as opening=X-Y, where X is
+// the roll of player 1 and Y is the roll of player 2. The rolls must differ.
+func parseOpeningRoll(param []byte) ([2]int8, bool) {
+	if !bytes.HasPrefix(param, []byte("opening=")) {
+		return [2]int8{}, false
+	}
+	rolls := bytes.Split(param[8:], []byte("-"))
+	if len(rolls) != 2 {
+		return [2]int8{}, false
+	}
+	var opening [2]int8
+	for i := range rolls {
+		roll, err := strconv.Atoi(string(rolls[i]))
+		if err != nil || roll < 1 || roll > 6 {
+			return [2]int8{}, false
+		}
+		opening[i] = int8(roll)
+	}
+	if opening[0] == opening[1] {
+		return [2]int8{}, false
+	}
+	return opening, true
+}
+
 func (g *serverGame) sendBoard(client *serverClient, forcedMove bool) {
 	if client.json {
 		ev := &bgammon.EventBoard{
@@ -222,27 +379,21 @@ func (g *serverGame) sendBoard(client *serverClient, forcedMove bool) {
 				ev.GameState.Winner = 1
 			}
 
+			switch ev.GameState.LastRollPlayer {
+			case 1:
+				ev.GameState.LastRollPlayer = 2
+			case 2:
+				ev.GameState.LastRollPlayer = 1
+			}
+
 			if ev.GameState.Roll1 == 0 || ev.GameState.Roll2 == 0 {
 				ev.GameState.Roll1, ev.GameState.Roll2 = ev.GameState.Roll2, ev.GameState.Roll1
 			}
 
 			// Flip board.
-			if g.Variant == bgammon.VariantTabula {
-				for space := int8(1); space <= 24; space++ {
-					ev.Board[space] = g.Game.Board[space] * -1
-				}
-			} else {
-				for space := int8(1); space <= 24; space++ {
-					ev.Board[space] = g.Game.Board[bgammon.FlipSpace(space, client.playerNumber, g.Variant)] * -1
-				}
-			}
-			ev.Board[bgammon.SpaceHomePlayer], ev.Board[bgammon.SpaceHomeOpponent] = ev.Board[bgammon.SpaceHomeOpponent]*-1, ev.Board[bgammon.SpaceHomePlayer]*-1
-			ev.Board[bgammon.SpaceBarPlayer], ev.Board[bgammon.SpaceBarOpponent] = ev.Board[bgammon.SpaceBarOpponent]*-1, ev.Board[bgammon.SpaceBarPlayer]*-1
+			ev.Board = bgammon.MirrorBoard(g.Game.Board, g.Variant)
 			ev.Moves = bgammon.FlipMoves(g.Game.Moves, client.playerNumber, g.Variant)
-			ev.GameState.Available = g.LegalMoves(false)
-			for i := range ev.GameState.Available {
-				ev.GameState.Available[i][0], ev.GameState.Available[i][1] = bgammon.FlipSpace(ev.GameState.Available[i][0], client.playerNumber, g.Variant), bgammon.FlipSpace(ev.GameState.Available[i][1], client.playerNumber, g.Variant)
-			}
+			ev.GameState.Available = g.LegalMovesForPlayer(client.playerNumber, false)
 		}
 
 		// Sort available moves.
@@ -252,7 +403,7 @@ func (g *serverGame) sendBoard(client *serverClient, forcedMove bool) {
 		return
 	}
 
-	scanner := bufio.NewScanner(bytes.NewReader(g.BoardState(client.playerNumber, false)))
+	scanner := bufio.NewScanner(bytes.NewReader(g.RenderBoard(client.playerNumber, false, &bgammon.RenderOptions{HomeLeft: client.flip})))
 	for scanner.Scan() {
 		client.sendNotice(string(scanner.Bytes()))
 	}
@@ -281,8 +432,38 @@ func (g *serverGame) eachClient(f func(client *serverClient)) {
 	}
 }
 
+// allowedNumber returns the player number the client is allowed to play as
+// once the players of the match are decided, or 0 when the client is not a
+// player.
+func (g *serverGame) allowedNumber(client *serverClient) int8 {
+	return allowedPlayer(client, g.allowed1, g.allowed2, g.account1, g.account2)
+}
+
+// allowedPlayer returns the player number the client is allowed to play as
+// given the usernames and account IDs of the allowed players, or 0 when the
+// client is not allowed to play. Players with an account are identified by
+// their account ID, allowing players who change their username to rejoin.
+// Guests are identified by their username.
+func allowedPlayer(client *serverClient, allowed1 []byte, allowed2 []byte, account1 int, account2 int) int8 {
+	var accountID int
+	if client.account != nil {
+		accountID = client.account.id
+	}
+	for i, allowed := range []struct {
+		name    []byte
+		account int
+	}{{allowed1, account1}, {allowed2, account2}} {
+		if (allowed.account != 0 && allowed.account == accountID) || (allowed.account == 0 && bytes.Equal(client.name, allowed.name)) {
+			return int8(i + 1)
+		}
+	}
+	return 0
+}
+
 func (g *serverGame) addClient(client *serverClient) (spectator bool) {
-	if g.allowed1 != nil && !bytes.Equal(client.name, g.allowed1) && !bytes.Equal(client.name, g.allowed2) {
+	if g.restored || g.demo {
+		spectator = true
+	} else if g.allowed1 != nil && g.allowedNumber(client) == 0 {
 		spectator = true
 	} else if g.client1 != nil && g.client2 != nil {
 		spectator = true
@@ -301,6 +482,7 @@ func (g *serverGame) addClient(client *serverClient) (spectator bool) {
 		}
 		ev.Player = string(client.name)
 		client.sendEvent(ev)
+		g.sendHistory(client)
 		g.sendBoard(client, false)
 		return spectator
 	}
@@ -361,12 +543,14 @@ func (g *serverGame) addClient(client *serverClient) (spectator bool) {
 		g.client2 = client
 		g.Player2.Name = string(client.name)
 		g.Player2.Rating = rating
+		g.Player2.Color = client.color
 		client.playerNumber = 2
 		playerNumber = 2
 	case g.client2 != nil:
 		g.client1 = client
 		g.Player1.Name = string(client.name)
 		g.Player1.Rating = rating
+		g.Player1.Color = client.color
 		client.playerNumber = 1
 		playerNumber = 1
 	default:
@@ -374,12 +558,14 @@ func (g *serverGame) addClient(client *serverClient) (spectator bool) {
 			g.client1 = client
 			g.Player1.Name = string(client.name)
 			g.Player1.Rating = rating
+			g.Player1.Color = client.color
 			client.playerNumber = 1
 			playerNumber = 1
 		} else {
 			g.client2 = client
 			g.Player2.Name = string(client.name)
 			g.Player2.Rating = rating
+			g.Player2.Color = client.color
 			client.playerNumber = 2
 			playerNumber = 2
 		}
@@ -471,13 +657,13 @@ func (g *serverGame) opponent(client *serverClient) *serverClient {
 	return nil
 }
 
-func (g *serverGame) listing(playerName []byte) *bgammon.GameListing {
+func (g *serverGame) listing(client *serverClient) *bgammon.GameListing {
 	if g.terminated() {
 		return nil
 	}
 
 	var playerCount int8
-	if len(g.allowed1) != 0 && (len(playerName) == 0 || (!bytes.Equal(g.allowed1, playerName) && !bytes.Equal(g.allowed2, playerName))) {
+	if len(g.allowed1) != 0 && (client == nil || g.allowedNumber(client) == 0) {
 		playerCount = 2
 	} else {
 		playerCount = g.playerCount()
@@ -513,6 +699,7 @@ func (g *serverGame) listing(playerName []byte) *bgammon.GameListing {
 }
 
 func (g *serverGame) recordEvent() {
+	g.turnReplay = len(g.replay)
 	r1, r2, r3 := g.Roll1, g.Roll2, g.Roll3
 	if r2 > r1 {
 		r1, r2 = r2, r1
@@ -533,9 +720,61 @@ func (g *serverGame) recordEvent() {
 	}
 	line = append(line, movesFormatted...)
 	g.replay = append(g.replay, line)
+
+	g.recordKeyframe()
+}
+
+// recordKeyframe records the state of the game in the replay when the number
+// of turns since the previous keyframe reaches the keyframe interval.
+func (g *serverGame) recordKeyframe() {
+	if replayKeyframeInterval <= 0 {
+		return
+	}
+	// The same player rolls again after playing an acey-deucey, so the player
+	// whose turn is next is not known until the reroll has been played.
+	aceyDeucey := ((g.Roll1 == 1 && g.Roll2 == 2) || (g.Roll1 == 2 && g.Roll2 == 1)) && len(g.Moves) == 2
+	if g.Variant == bgammon.VariantAceyDeucey && (g.Reroll || aceyDeucey) {
+		return
+	}
+	var turns int
+	for i := len(g.replay) - 1; i >= 0; i-- {
+		fields := bytes.Fields(g.replay[i])
+		if len(fields) < 2 {
+			continue
+		} else if fields[1][0] == bgammon.ReplayEventKeyframe {
+			break
+		} else if fields[1][0] == bgammon.ReplayEventRoll {
+			turns++
+		}
+	}
+	if turns < replayKeyframeInterval {
+		return
+	}
+
+	board := make([][]byte, len(g.Board))
+	for i, v := range g.Board {
+		board[i] = []byte(strconv.Itoa(int(v)))
+	}
+	entered := []byte("00")
+	if g.Player1.Entered {
+		entered[0] = '1'
+	}
+	if g.Player2.Entered {
+		entered[1] = '1'
+	}
+	var next int8 = 1
+	if g.Turn == 1 {
+		next = 2
+	}
+	g.replay = append(g.replay, []byte(fmt.Sprintf("%d k %s %d %d %s", next, bytes.Join(board, []byte(",")), g.DoubleValue, g.DoublePlayer, entered)))
 }
 
 func (g *serverGame) nextTurn(reroll bool) {
+	g.draw = 0
+	g.takeback, g.takebackRequest = nil, 0
+	if !reroll && g.Winner == 0 {
+		g.takeback, g.takebackReplay = g.TurnStart(), g.turnReplay
+	}
 	g.Game.NextTurn(reroll)
 	if reroll {
 		return
@@ -569,6 +808,10 @@ func (g *serverGame) nextTurn(reroll bool) {
 				client.sendEvent(ev)
 			})
 
+			if g.passDancedTurn() {
+				return
+			}
+
 			// Play forced moves automatically.
 			forcedMove := g.playForcedMoves()
 			if forcedMove && len(g.LegalMoves(false)) == 0 {
@@ -587,55 +830,87 @@ func (g *serverGame) nextTurn(reroll bool) {
 	})
 }
 
+func (g *serverGame) replayHeader() []byte {
+	color1, color2 := g.Player1.Color, g.Player2.Color
+	if color1 == "" {
+		color1 = "clear"
+	}
+	if color2 == "" {
+		color2 = "clear"
+	}
+	return []byte(fmt.Sprintf("i %d %s %s %d %d %d %d %d %d %s %s", g.Started.Unix(), g.allowed1, g.allowed2, g.Points, g.Player1.Points, g.Player2.Points, g.Winner, g.DoubleValue, g.Variant, color1, color2))
+}
+
 func (g *serverGame) addReplayHeader() {
-	g.replay = append([][]byte{[]byte(fmt.Sprintf("i %d %s %s %d %d %d %d %d %d", g.Started.Unix(), g.allowed1, g.allowed2, g.Points, g.Player1.Points, g.Player2.Points, g.Winner, g.DoubleValue, g.Variant))}, g.replay...)
+	g.replay = append([][]byte{g.replayHeader()}, g.replay...)
 }
 
-func (g *serverGame) handleWin() bool {
-	if g.Winner == 0 {
-		return false
+// recordChat records a chat message sent during the match, discarding the
+// oldest message once chatHistory messages have been recorded.
+func (g *serverGame) recordChat(ev *bgammon.EventSay) {
+	g.chat = append(g.chat, ev)
+	if len(g.chat) > chatHistory {
+		g.chat = g.chat[len(g.chat)-chatHistory:]
 	}
-	var opponent int8 = 1
-	opponentHome := bgammon.SpaceHomePlayer
-	opponentEntered := g.Player1.Entered
-	playerBar := bgammon.SpaceBarPlayer
-	if g.Winner == 1 {
-		opponent = 2
-		opponentHome = bgammon.SpaceHomeOpponent
-		opponentEntered = g.Player2.Entered
-		playerBar = bgammon.SpaceBarOpponent
-	}
-
-	backgammon := bgammon.PlayerCheckers(g.Board[playerBar], opponent) != 0
-	if !backgammon {
-		homeStart, homeEnd := bgammon.HomeRange(g.Winner, g.Variant)
-		bgammon.IterateSpaces(homeStart, homeEnd, g.Variant, func(space int8, spaceCount int8) {
-			if bgammon.PlayerCheckers(g.Board[space], opponent) != 0 {
-				backgammon = true
-			}
+}
+
+// sendHistory sends the moves played so far during the current game and the
+// recent chat messages to a client which has joined the match in progress.
+func (g *serverGame) sendHistory(client *serverClient) {
+	if len(g.replay) != 0 {
+		replay := g.replay
+		if g.Winner == 0 {
+			replay = append([][]byte{g.replayHeader()}, g.replay...)
+		}
+		client.sendEvent(&bgammon.EventReplay{
+			ID:      -1,
+			Content: bytes.Join(replay, []byte("\n")),
 		})
 	}
+	for _, ev := range g.chat {
+		client.sendEvent(ev)
+	}
+}
 
-	var winPoints int8
-	switch g.Variant {
-	case bgammon.VariantAceyDeucey:
-		for space := int8(0); space < bgammon.BoardSpaces; space++ {
-			if (space == bgammon.SpaceHomePlayer || space == bgammon.SpaceHomeOpponent) && opponentEntered {
-				continue
-			}
-			winPoints += bgammon.PlayerCheckers(g.Board[space], opponent)
-		}
-	case bgammon.VariantTabula:
-		winPoints = 1
-	default:
-		if backgammon {
-			winPoints = 3 // Award backgammon.
-		} else if g.Board[opponentHome] == 0 {
-			winPoints = 2 // Award gammon.
-		} else {
-			winPoints = 1
-		}
+// winEvent returns the event sent when the current game ends. The score of
+// the winner must already include the provided stake.
+func (g *serverGame) winEvent(winType int8, stake int8) *bgammon.EventWin {
+	ev := &bgammon.EventWin{
+		Points:    stake,
+		Winner:    g.Winner,
+		WinType:   winType,
+		CubeValue: g.DoubleValue,
+		Score1:    int(g.Player1.Points),
+		Score2:    int(g.Player2.Points),
+		MatchOver: g.Player1.Points >= g.Points || g.Player2.Points >= g.Points,
 	}
+	ev.Player = g.Player1.Name
+	if g.Winner == 2 {
+		ev.Player = g.Player2.Name
+	}
+	return ev
+}
+
+// sendWinEvent sends the provided event to the client. The winner and scores
+// are reversed for player 2, matching the board sent to JSON clients.
+func (g *serverGame) sendWinEvent(client *serverClient, ev *bgammon.EventWin) {
+	if client.playerNumber == 2 && g.client2 == client {
+		flipped := *ev
+		flipped.Winner = 3 - ev.Winner
+		flipped.Score1, flipped.Score2 = ev.Score2, ev.Score1
+		ev = &flipped
+	}
+	client.sendEvent(ev)
+}
+
+func (g *serverGame) handleWin() bool {
+	if g.Winner == 0 {
+		return false
+	}
+	winType, stake := g.WinType(), g.Stake()
+
+	// Finished games may not be taken back.
+	g.takeback, g.takebackRequest = nil, 0
 
 	g.addReplayHeader()
 
@@ -660,21 +935,16 @@ func (g *serverGame) handleWin() bool {
 	line = append(line, movesFormatted...)
 	g.replay = append(g.replay, line)
 
-	winEvent := &bgammon.EventWin{
-		Points: winPoints * g.DoubleValue,
-	}
 	var reset bool
 	if g.Winner == 1 {
-		winEvent.Player = g.Player1.Name
-		g.Player1.Points = g.Player1.Points + winPoints*g.DoubleValue
+		g.Player1.Points = g.Player1.Points + stake
 		if g.Player1.Points < g.Points {
 			reset = true
 		} else {
 			g.Ended = time.Now()
 		}
 	} else {
-		winEvent.Player = g.Player2.Name
-		g.Player2.Points = g.Player2.Points + winPoints*g.DoubleValue
+		g.Player2.Points = g.Player2.Points + stake
 		if g.Player2.Points < g.Points {
 			reset = true
 		} else {
@@ -682,23 +952,24 @@ func (g *serverGame) handleWin() bool {
 		}
 	}
 
-	winType := winPoints
-	if g.Variant != bgammon.VariantBackgammon {
-		winType = 1
-	}
-	err := recordGameResult(g, winType, g.replay)
-	if err != nil {
-		log.Fatalf("failed to record game result: %s", err)
+	winEvent := g.winEvent(winType, stake)
+
+	if !g.demo {
+		_, err := recordGameResult(g, winType, g.replay)
+		if err != nil {
+			log.Fatalf("failed to record game result: %s", err)
+		}
 	}
 
 	if !reset {
-		err := recordMatchResult(g, matchTypeCasual)
-		if err != nil {
-			log.Fatalf("failed to record match result: %s", err)
+		if !g.demo {
+			err := recordMatchResult(g, matchTypeCasual)
+			if err != nil {
+				log.Fatalf("failed to record match result: %s", err)
+			}
 		}
 	} else {
-		g.Reset()
-		g.replay = g.replay[:0]
+		g.resetGame()
 	}
 
 	if g.client1 != nil && g.client1.account != nil {
@@ -709,7 +980,154 @@ func (g *serverGame) handleWin() bool {
 	}
 	g.eachClient(func(client *serverClient) {
 		g.sendBoard(client, false)
-		client.sendEvent(winEvent)
+		g.sendWinEvent(client, winEvent)
+	})
+	return true
+}
+
+// resetGame resets the board and replay before the next game of the match.
+// Turns played and double offers declined during the previous game may no
+// longer be taken back or undone.
+func (g *serverGame) resetGame() {
+	g.Reset()
+	g.replay = g.replay[:0]
+	g.takeback, g.takebackRequest = nil, 0
+	g.declined, g.declinedReplay, g.declinedRequest, g.declinedGame = nil, nil, 0, 0
+}
+
+// rated returns whether the result of the match will affect the ratings of
+// the players.
+func (g *serverGame) rated() bool {
+	return g.account1 != 0 && g.account2 != 0 && g.account1 != g.account2
+}
+
+// offerDraw offers a draw on behalf of the provided client, or accepts the
+// draw offered by the client's opponent. When a draw is agreed, the current
+// game is abandoned without changing the score and true is returned.
+func (g *serverGame) offerDraw(client *serverClient) bool {
+	if g.draw == 0 || g.draw == client.playerNumber {
+		g.draw = client.playerNumber
+		ev := &bgammon.EventDrawOffer{}
+		ev.Player = string(client.name)
+		g.eachClient(func(client *serverClient) {
+			client.sendEvent(ev)
+		})
+		return false
+	}
+
+	g.draw = 0
+	g.resetGame()
+
+	ev := &bgammon.EventDraw{}
+	ev.Player = string(client.name)
+	g.eachClient(func(client *serverClient) {
+		client.sendEvent(ev)
+		g.sendBoard(client, false)
+	})
+	return true
+}
+
+// requestPause requests to pause or resume the match on behalf of the provided
+// client, or agrees to the request of the client's opponent. When both players
+// agree, the match is paused or resumed and true is returned.
+func (g *serverGame) requestPause(client *serverClient, pause bool) bool {
+	if g.pauseRequestedBy == 0 || g.pauseRequestedBy == client.playerNumber {
+		g.pauseRequestedBy = client.playerNumber
+		opponent := g.opponent(client)
+		if opponent != nil {
+			if pause {
+				opponent.sendNotice(gotext.GetD(opponent.language, "Your opponent would like to pause the match."))
+			} else {
+				opponent.sendNotice(gotext.GetD(opponent.language, "Your opponent would like to resume the match."))
+			}
+		}
+		client.sendNotice(gotext.GetD(client.language, "Request sent."))
+		return false
+	}
+	g.setPaused(pause)
+	return true
+}
+
+// setPaused pauses or resumes the match. The turn clock is stopped while the
+// match is paused.
+func (g *serverGame) setPaused(paused bool) {
+	g.paused = paused
+	g.pauseRequestedBy = 0
+	if paused {
+		g.pauseStarted = time.Now()
+		g.NextPartialTurn(0)
+		g.eachClient(func(client *serverClient) {
+			client.sendEvent(&bgammon.EventPaused{})
+		})
+		return
+	}
+	g.pauseTotal += time.Since(g.pauseStarted)
+	g.NextPartialTurn(g.Turn)
+	g.eachClient(func(client *serverClient) {
+		client.sendEvent(&bgammon.EventResumed{})
+	})
+}
+
+// requestUndoDecline requests to undo declining a double offer on behalf of
+// the provided client, or agrees to the request of the client's opponent. When
+// the opponent agrees, the game is restored to the state before the double
+// offer was declined and true is returned.
+func (g *serverGame) requestUndoDecline(client *serverClient) bool {
+	if g.declinedRequest == 0 {
+		g.declinedRequest = client.playerNumber
+		opponent := g.opponent(client)
+		if opponent != nil {
+			opponent.sendNotice(gotext.GetD(opponent.language, "Your opponent would like to undo declining your double offer."))
+		}
+		client.sendNotice(gotext.GetD(client.language, "Request to undo declining the double offer sent."))
+		return false
+	}
+
+	// The restored game is recorded again once it ends.
+	err := deleteGameResult(g.declinedGame)
+	if err != nil {
+		log.Fatalf("failed to delete game result: %s", err)
+	}
+
+	g.Game = g.declined
+	g.replay = g.declinedReplay
+	g.declined, g.declinedReplay, g.declinedRequest, g.declinedGame = nil, nil, 0, 0
+	receiver := int8(1)
+	if g.Turn == 1 {
+		receiver = 2
+	}
+	g.NextPartialTurn(receiver)
+
+	g.eachClient(func(client *serverClient) {
+		client.sendNotice(gotext.GetD(client.language, "The double offer was restored."))
+		g.sendBoard(client, false)
+	})
+	return true
+}
+
+// requestTakeback requests to take back the previous turn on behalf of the
+// provided client, or agrees to the request of the client's opponent. When the
+// opponent agrees, the game is restored to the start of the previous turn and
+// true is returned.
+func (g *serverGame) requestTakeback(client *serverClient) bool {
+	if g.takebackRequest == 0 {
+		g.takebackRequest = client.playerNumber
+		opponent := g.opponent(client)
+		if opponent != nil {
+			opponent.sendNotice(gotext.GetD(opponent.language, "Your opponent would like to take back their previous turn."))
+		}
+		client.sendNotice(gotext.GetD(client.language, "Take-back request sent."))
+		return false
+	}
+
+	g.Game = g.takeback
+	g.replay = g.replay[:g.takebackReplay]
+	g.takeback, g.takebackRequest = nil, 0
+	g.NextPartialTurn(g.Turn)
+
+	g.eachClient(func(client *serverClient) {
+		client.sendNotice(gotext.GetD(client.language, "The previous turn was taken back."))
+		g.sendBoard(client, false)
 	})
 	return true
 }
@@ -30,12 +30,33 @@ const clientTimeout = 40 * time.Second
 
 const inactiveLimit = 600 // 10 minutes.
 
+// pauseLimit is the total amount of time a match may remain paused. Matches
+// are resumed automatically once the limit is reached.
+const pauseLimit = 15 * time.Minute
+
+const rollCooldown = 250 * time.Millisecond
+
+const defaultRematchTokenExpiry = 10 * time.Minute
+
+// rematchTokenPrefix is prepended to rematch tokens. Usernames may not contain
+// hyphens, so tokens are never mistaken for player names when joining.
+const rematchTokenPrefix = "rematch-"
+
 var allowDebugCommands bool
 
+// replayKeyframeInterval is the number of turns between keyframes recorded in
+// replays. Keyframes are not recorded when the interval is zero.
+var replayKeyframeInterval int
+
+// detailedPassNotices is whether the notice sent when a turn is passed
+// automatically explains why the player was unable to move.
+var detailedPassNotices = true
+
 var (
 	onlyNumbers            = regexp.MustCompile(`^[0-9]+$`)
 	guestName              = regexp.MustCompile(`^guest[0-9]+$`)
 	alphaNumericUnderscore = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+	checkerColor           = regexp.MustCompile(`^(#[0-9A-Fa-f]{6}|[A-Za-z]{1,16}|[!-~])$`)
 )
 
 //go:embed locales
@@ -52,6 +73,27 @@ type serverCommand struct {
 	command []byte
 }
 
+// rematchToken is a one-time token which recreates a finished match between
+// the same two players.
+type rematchToken struct {
+	allowed1 []byte
+	allowed2 []byte
+	account1 int
+	account2 int
+	name     []byte
+	password []byte
+	variant  int8
+	points   int8
+	gameID   int // Set once the first player has joined.
+	expires  time.Time
+
+	noUndo          bool
+	allowUnusedDice bool
+	autoStart       bool
+	noCube          bool
+	mustFillLow     bool
+}
+
 type server struct {
 	clients      []*serverClient
 	games        []*serverGame
@@ -91,6 +133,14 @@ type server struct {
 	relayChat bool // Chats are not relayed normally. This option is only used by local servers.
 	verbose   bool
 
+	rematchTokens      map[string]*rematchToken
+	rematchTokenExpiry time.Duration
+
+	noUndo          bool // Whether players may not undo pending moves before ending their turn.
+	allowUnusedDice bool // Whether players may end their turn without using as many dice as possible.
+
+	matchLengths []int // Number of points players may choose to play to when creating a match. When empty, any number of points from 1 to 99 may be chosen.
+
 	shutdownTime   time.Time
 	shutdownReason string
 }
@@ -107,6 +157,9 @@ func NewServer(tz string, dataSource string, mailServer string, passwordSalt str
 		resetSalt:    resetSalt,
 		relayChat:    relayChat,
 		verbose:      verbose,
+
+		rematchTokens:      make(map[string]*rematchToken),
+		rematchTokenExpiry: defaultRematchTokenExpiry,
 	}
 	s.loadLocales()
 
@@ -201,6 +254,174 @@ func (s *server) matchLanguage(identifier []byte) []byte {
 	return s.languageNames[index]
 }
 
+// SetRematchTokenExpiry sets how long rematch tokens remain valid after they
+// are generated.
+func (s *server) SetRematchTokenExpiry(expiry time.Duration) {
+	s.rematchTokenExpiry = expiry
+}
+
+// newRematchToken generates a rematch token for the provided finished match.
+func (s *server) newRematchToken(g *serverGame) string {
+	s.pruneRematchTokens()
+	b := make([]byte, 12)
+	_, err := rand.Read(b)
+	if err != nil {
+		log.Fatalf("failed to generate rematch token: %s", err)
+	}
+	token := rematchTokenPrefix + base64.RawURLEncoding.EncodeToString(b)
+	s.rematchTokens[token] = &rematchToken{
+		allowed1: g.allowed1,
+		allowed2: g.allowed2,
+		account1: g.account1,
+		account2: g.account2,
+		name:     g.name,
+		password: g.password,
+		variant:  g.Variant,
+		points:   g.Points,
+		expires:  time.Now().Add(s.rematchTokenExpiry),
+
+		noUndo:          g.NoUndo,
+		allowUnusedDice: g.AllowUnusedDice,
+		autoStart:       g.AutoStart,
+		noCube:          g.NoCube,
+		mustFillLow:     g.MustFillLow,
+	}
+	return token
+}
+
+func (s *server) pruneRematchTokens() {
+	now := time.Now()
+	for token, t := range s.rematchTokens {
+		if now.After(t.expires) {
+			delete(s.rematchTokens, token)
+		}
+	}
+}
+
+// joinRematch adds the client to the match created by the provided rematch
+// token, creating the match when the client is the first player to join.
+func (s *server) joinRematch(client *serverClient, token string) {
+	s.pruneRematchTokens()
+	t := s.rematchTokens[token]
+	if t == nil {
+		client.sendEvent(&bgammon.EventFailedJoin{
+			Reason: gotext.GetD(client.language, "Invalid or expired rematch token."),
+		})
+		return
+	} else if allowedPlayer(client, t.allowed1, t.allowed2, t.account1, t.account2) == 0 {
+		client.sendEvent(&bgammon.EventFailedJoin{
+			Reason: gotext.GetD(client.language, "This rematch token was issued to other players."),
+		})
+		return
+	} else if !s.shutdownTime.IsZero() {
+		client.sendEvent(&bgammon.EventFailedJoin{
+			Reason: gotext.GetD(client.language, "Failed to create match: The server is shutting down. Reason: %s", s.shutdownReason),
+		})
+		return
+	}
+
+	s.gamesLock.Lock()
+	var g *serverGame
+	if t.gameID != 0 {
+		for _, game := range s.games {
+			if game.id == t.gameID && !game.terminated() {
+				g = game
+				break
+			}
+		}
+	}
+	if g == nil {
+		g = newServerGame(<-s.newGameIDs, t.variant)
+		g.name = t.name
+		g.Points = t.points
+		g.password = t.password
+		g.NoUndo = t.noUndo
+		g.AllowUnusedDice = t.allowUnusedDice
+		g.AutoStart = t.autoStart
+		g.NoCube = t.noCube
+		g.MustFillLow = t.mustFillLow
+		g.allowed1, g.allowed2 = t.allowed1, t.allowed2
+		g.account1, g.account2 = t.account1, t.account2
+		s.games = append(s.games, g)
+		t.gameID = g.id
+	}
+	g.addClient(client)
+	if g.client1 != nil && g.client2 != nil {
+		delete(s.rematchTokens, token)
+	}
+	s.gamesLock.Unlock()
+
+	client.sendNotice(fmt.Sprintf(gotext.GetD(client.language, "Joined match: %s"), g.name))
+	s.autoStart(g)
+}
+
+// autoStart rolls the opening roll on behalf of both players once both players
+// have joined a match which starts automatically. The rolls are handled like
+// any other roll command, so ties are re-rolled and each roll is sent to the
+// clients.
+func (s *server) autoStart(g *serverGame) {
+	if !g.AutoStart || g.client1 == nil || g.client2 == nil || g.Turn != 0 || g.Roll1 != 0 || g.Roll2 != 0 || g.Winner != 0 {
+		return
+	}
+	client1, client2 := g.client1, g.client2
+	go func() {
+		s.commands <- serverCommand{client: client1, command: []byte(bgammon.CommandRoll)}
+		s.commands <- serverCommand{client: client2, command: []byte(bgammon.CommandRoll)}
+	}()
+}
+
+// SetReplayKeyframeInterval sets the number of turns between keyframes
+// recorded in replays. Keyframes allow clients to seek within long replays
+// without replaying every prior turn. Keyframes are not recorded when the
+// interval is zero.
+func (s *server) SetReplayKeyframeInterval(turns int) {
+	replayKeyframeInterval = turns
+}
+
+// SetDetailedPassNotices sets whether the notice sent when a turn is passed
+// automatically includes the roll and the number of rolls which would have
+// failed to enter a checker from the bar. Tabula games always use the shorter
+// notice.
+func (s *server) SetDetailedPassNotices(detailed bool) {
+	detailedPassNotices = detailed
+}
+
+// SetAllowUnusedDice sets whether players of newly created matches may end their
+// turn at any time. When disabled, players must use as many dice as possible.
+// This should remain disabled on servers hosting rated play.
+func (s *server) SetAllowUnusedDice(allow bool) {
+	s.allowUnusedDice = allow
+}
+
+// SetMatchLengths sets the number of points players may choose to play to when
+// creating a match. When no lengths are provided, any number of points from 1
+// to 99 may be chosen.
+func (s *server) SetMatchLengths(lengths []int) {
+	s.matchLengths = lengths
+}
+
+// validMatchLength returns whether players may create a match played to the
+// provided number of points.
+func (s *server) validMatchLength(points int) bool {
+	if points < 1 || points > 99 {
+		return false
+	} else if len(s.matchLengths) == 0 {
+		return true
+	}
+	for _, length := range s.matchLengths {
+		if points == length {
+			return true
+		}
+	}
+	return false
+}
+
+// SetNoUndo sets whether players of newly created matches are prevented from
+// undoing pending moves before ending their turn.
+func (s *server) SetNoUndo(noUndo bool) {
+	s.noUndo = noUndo
+}
+
 func (s *server) ListenLocal() chan net.Conn {
 	conns := make(chan net.Conn)
 	go s.handleLocal(conns)
@@ -299,10 +520,22 @@ func (s *server) handleGames() {
 				}
 			}
 
-			if !g.terminated() {
+			if g.paused && g.pauseTotal+time.Since(g.pauseStarted) >= pauseLimit {
+				g.setPaused(false)
+			}
+
+			// End demo matches once no spectators remain.
+			if g.demo && len(g.spectators) == 0 && !g.terminated() {
+				g.eachClient(func(client *serverClient) {
+					client.Terminate("")
+				})
+				g.client1, g.client2 = nil, nil
+			}
+
+			if !g.terminated() || (g.restored && len(g.spectators) != 0) {
 				s.games[i] = g
 				i++
-			} else if g.Winner == 0 && (g.inactive != 0 || g.forefeit != 0) {
+			} else if !g.restored && !g.demo && g.Winner == 0 && (g.inactive != 0 || g.forefeit != 0) {
 				if g.inactive != 0 {
 					g.Winner = 1
 					if g.inactive == 1 {
@@ -322,7 +555,7 @@ func (s *server) handleGames() {
 				}
 				g.replay = append(g.replay, []byte(fmt.Sprintf("%d t", opponent)))
 
-				err := recordGameResult(g, 4, g.replay)
+				_, err := recordGameResult(g, 4, g.replay)
 				if err != nil {
 					log.Fatalf("failed to record game result: %s", err)
 				}
@@ -2,6 +2,7 @@ package server
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"log"
 	"strconv"
@@ -184,6 +185,7 @@ COMMANDS:
 					cmd.client.accountID = a.id
 					cmd.client.name = name
 					cmd.client.autoplay = a.autoplay
+					cmd.client.flip = a.flip
 				} else {
 					cmd.client.accountID = 0
 					if !randomUsername && !bytes.HasPrefix(username, []byte("BOT_")) && !bytes.HasPrefix(username, []byte("Guest_")) {
@@ -231,9 +233,10 @@ COMMANDS:
 					}
 
 					var rejoin bool
-					if bytes.Equal(cmd.client.name, g.allowed1) {
+					switch g.allowedNumber(cmd.client) {
+					case 1:
 						rejoin = g.rejoin1
-					} else if bytes.Equal(cmd.client.name, g.allowed2) {
+					case 2:
 						rejoin = g.rejoin2
 					}
 					if rejoin {
@@ -252,7 +255,7 @@ COMMANDS:
 		clientGame := s.gameByClient(cmd.client)
 		if clientGame != nil && clientGame.client1 != cmd.client && clientGame.client2 != cmd.client {
 			switch keyword {
-			case bgammon.CommandHelp, "h", bgammon.CommandJSON, bgammon.CommandList, "ls", bgammon.CommandBoard, "b", bgammon.CommandLeave, "l", bgammon.CommandReplay, bgammon.CommandSet, bgammon.CommandPong, bgammon.CommandDisconnect, bgammon.CommandMOTD, bgammon.CommandBroadcast, bgammon.CommandShutdown:
+			case bgammon.CommandHelp, "h", bgammon.CommandJSON, bgammon.CommandList, "ls", bgammon.CommandBoard, "b", bgammon.CommandLeave, "l", bgammon.CommandReplay, bgammon.CommandSet, bgammon.CommandPong, bgammon.CommandDisconnect, bgammon.CommandMOTD, bgammon.CommandBroadcast, bgammon.CommandShutdown, bgammon.CommandDump, bgammon.CommandRestore:
 				// These commands are allowed to be used by spectators.
 			default:
 				cmd.client.sendNotice(gotext.GetD(cmd.client.language, "Command ignored: You are spectating this match."))
@@ -315,6 +318,7 @@ COMMANDS:
 			ev.Player = string(cmd.client.name)
 			opponent.sendEvent(ev)
 			if s.relayChat {
+				clientGame.recordChat(ev)
 				for _, spectator := range clientGame.spectators {
 					spectator.sendEvent(ev)
 				}
@@ -324,7 +328,7 @@ COMMANDS:
 
 			s.gamesLock.RLock()
 			for _, g := range s.games {
-				listing := g.listing(cmd.client.name)
+				listing := g.listing(cmd.client)
 				if listing == nil {
 					continue
 				}
@@ -394,10 +398,44 @@ COMMANDS:
 				}
 			}
 
+			var opening [2]int8
+			var autoStart bool
+			var noCube bool
+		OPTIONS:
+			for len(gameName) != 0 {
+				fields := bytes.SplitN(gameName, []byte(" "), 2)
+				switch {
+				case bytes.HasPrefix(fields[0], []byte("opening=")):
+					var ok bool
+					opening, ok = parseOpeningRoll(fields[0])
+					if !ok {
+						cmd.client.sendNotice(gotext.GetD(cmd.client.language, "Failed to create match: The opening roll must be specified as two different dice, such as opening=3-1."))
+						continue COMMANDS
+					}
+				case bytes.Equal(fields[0], []byte("autostart")):
+					autoStart = true
+				case bytes.Equal(fields[0], []byte("nocube")):
+					noCube = true
+				default:
+					break OPTIONS
+				}
+				gameName = nil
+				if len(fields) > 1 {
+					gameName = fields[1]
+				}
+			}
+
 			points, err := strconv.Atoi(string(gamePoints))
 			if err != nil || points < 1 || points > 99 {
 				sendUsage()
 				continue
+			} else if !s.validMatchLength(points) {
+				lengths := make([]string, len(s.matchLengths))
+				for i := range s.matchLengths {
+					lengths[i] = strconv.Itoa(s.matchLengths[i])
+				}
+				cmd.client.sendNotice(fmt.Sprintf(gotext.GetD(cmd.client.language, "Failed to create match: Matches may only be played to the following number of points: %s"), strings.Join(lengths, ", ")))
+				continue
 			}
 
 			// Set default game name.
@@ -414,6 +452,11 @@ COMMANDS:
 			g.name = gameName
 			g.Points = int8(points)
 			g.password = gamePassword
+			g.NoUndo = s.noUndo
+			g.AllowUnusedDice = s.allowUnusedDice
+			g.opening = opening
+			g.AutoStart = autoStart
+			g.NoCube = noCube
 			g.addClient(cmd.client)
 
 			s.gamesLock.Lock()
@@ -421,6 +464,7 @@ COMMANDS:
 			s.gamesLock.Unlock()
 
 			cmd.client.sendNotice(fmt.Sprintf(gotext.GetD(cmd.client.language, "Created match: %s"), g.name))
+			cmd.client.sendNotice(fmt.Sprintf(gotext.GetD(cmd.client.language, "This is a %d-point match."), g.Points))
 
 			if len(g.password) == 0 {
 				cmd.client.sendNotice("Note: Please be patient as you wait for another player to join the match. A chime will sound when another player joins. While you wait, join the bgammon.org community via Discord, Matrix or IRC at bgammon.org/community")
@@ -442,6 +486,11 @@ COMMANDS:
 				continue
 			}
 
+			if bytes.HasPrefix(params[0], []byte(rematchTokenPrefix)) {
+				s.joinRematch(cmd.client, string(params[0]))
+				continue
+			}
+
 			var joinGameID int
 			if onlyNumbers.Match(params[0]) {
 				gameID, err := strconv.Atoi(string(params[0]))
@@ -502,7 +551,10 @@ COMMANDS:
 					cmd.client.sendNotice(fmt.Sprintf(gotext.GetD(cmd.client.language, "Joined match: %s"), g.name))
 					if spectator {
 						cmd.client.sendNotice(gotext.GetD(cmd.client.language, "You are spectating this match. Chat messages are not relayed."))
+					} else {
+						cmd.client.sendNotice(fmt.Sprintf(gotext.GetD(cmd.client.language, "This is a %d-point match."), g.Points))
 					}
+					s.autoStart(g)
 					continue COMMANDS
 				}
 			}
@@ -532,6 +584,9 @@ COMMANDS:
 				continue
 			} else if clientGame.Winner != 0 {
 				continue
+			} else if clientGame.paused {
+				cmd.client.sendNotice(gotext.GetD(cmd.client.language, "The match is paused."))
+				continue
 			}
 
 			if clientGame.Turn != cmd.client.playerNumber {
@@ -590,7 +645,13 @@ COMMANDS:
 				PlayerNumber: cmd.client.playerNumber,
 				Available:    clientGame.LegalMoves(false),
 			}
+			var declined *bgammon.Game
+			var declinedReplay [][]byte
 			if gameState.MayDecline() {
+				declined = clientGame.Copy(false)
+				declinedReplay = make([][]byte, len(clientGame.replay))
+				copy(declinedReplay, clientGame.replay)
+
 				clientGame.Winner = opponent.playerNumber
 				clientGame.NextPartialTurn(opponent.playerNumber)
 
@@ -611,6 +672,7 @@ COMMANDS:
 				clientGame.replay = append(clientGame.replay, []byte(fmt.Sprintf("%d t", cmd.client.playerNumber)))
 			}
 			clientGame.Ended = time.Now()
+			clientGame.takeback, clientGame.takebackRequest = nil, 0
 
 			var reset bool
 			if clientGame.Winner == 1 {
@@ -623,8 +685,10 @@ COMMANDS:
 			clientGame.addReplayHeader()
 
 			var winEvent *bgammon.EventWin
+			var gameID int
 			if clientGame.Winner != 0 {
-				err := recordGameResult(clientGame, 4, clientGame.replay)
+				var err error
+				gameID, err = recordGameResult(clientGame, 4, clientGame.replay)
 				if err != nil {
 					log.Fatalf("failed to record game result: %s", err)
 				}
@@ -634,27 +698,25 @@ COMMANDS:
 					if err != nil {
 						log.Fatalf("failed to record match result: %s", err)
 					}
-
-					winEvent = &bgammon.EventWin{
-						Points: clientGame.DoubleValue,
-					}
-					if clientGame.Winner == 1 {
-						winEvent.Player = clientGame.Player1.Name
-					} else {
-						winEvent.Player = clientGame.Player2.Name
-					}
 				}
+
+				winEvent = clientGame.winEvent(1, clientGame.DoubleValue)
 			}
 
 			if reset {
-				clientGame.Reset()
-				clientGame.replay = clientGame.replay[:0]
+				clientGame.resetGame()
+
+				// Declined double offers may be undone until the next game begins.
+				if declined != nil && !clientGame.rated() {
+					clientGame.declined, clientGame.declinedReplay, clientGame.declinedRequest = declined, declinedReplay, 0
+					clientGame.declinedGame = gameID
+				}
 			}
 
 			clientGame.eachClient(func(client *serverClient) {
 				clientGame.sendBoard(client, false)
 				if winEvent != nil {
-					client.sendEvent(winEvent)
+					clientGame.sendWinEvent(client, winEvent)
 				}
 			})
 		case bgammon.CommandRoll, "r":
@@ -665,6 +727,11 @@ COMMANDS:
 				continue
 			} else if clientGame.Winner != 0 {
 				continue
+			} else if clientGame.paused {
+				cmd.client.sendEvent(&bgammon.EventFailedRoll{
+					Reason: gotext.GetD(cmd.client.language, "The match is paused."),
+				})
+				continue
 			}
 
 			opponent := clientGame.opponent(cmd.client)
@@ -675,6 +742,21 @@ COMMANDS:
 				continue
 			}
 
+			if time.Since(cmd.client.lastRoll) < rollCooldown {
+				cmd.client.sendEvent(&bgammon.EventFailedRoll{
+					Reason: gotext.GetD(cmd.client.language, "Please wait before rolling again."),
+				})
+				continue
+			}
+			cmd.client.lastRoll = time.Now()
+
+			if reason := clientGame.rollError(cmd.client); reason != "" {
+				cmd.client.sendEvent(&bgammon.EventFailedRoll{
+					Reason: reason,
+				})
+				continue
+			}
+
 			if !clientGame.roll(cmd.client.playerNumber) {
 				cmd.client.sendEvent(&bgammon.EventFailedRoll{
 					Reason: gotext.GetD(cmd.client.language, "It is not your turn to roll."),
@@ -781,6 +863,10 @@ COMMANDS:
 
 			clientGame.NextPartialTurn(clientGame.Turn)
 
+			if clientGame.passDancedTurn() {
+				continue
+			}
+
 			forcedMove := clientGame.playForcedMoves()
 			if forcedMove && len(clientGame.LegalMoves(false)) == 0 {
 				chooseRoll := clientGame.Variant == bgammon.VariantAceyDeucey && ((clientGame.Roll1 == 1 && clientGame.Roll2 == 2) || (clientGame.Roll1 == 2 && clientGame.Roll2 == 1)) && len(clientGame.Moves) == 2
@@ -805,6 +891,11 @@ COMMANDS:
 			} else if clientGame.Winner != 0 {
 				clientGame.sendBoard(cmd.client, false)
 				continue
+			} else if clientGame.paused {
+				cmd.client.sendEvent(&bgammon.EventFailedMove{
+					Reason: gotext.GetD(cmd.client.language, "The match is paused."),
+				})
+				continue
 			}
 
 			if clientGame.Turn != cmd.client.playerNumber {
@@ -899,6 +990,9 @@ COMMANDS:
 
 			if len(clientGame.Moves) == 0 {
 				continue
+			} else if clientGame.NoUndo {
+				cmd.client.sendNotice(gotext.GetD(cmd.client.language, "Undoing moves is not allowed in this match."))
+				continue
 			}
 
 			l := len(clientGame.Moves)
@@ -926,6 +1020,11 @@ COMMANDS:
 				continue
 			} else if clientGame.Winner != 0 {
 				continue
+			} else if clientGame.paused {
+				cmd.client.sendEvent(&bgammon.EventFailedOk{
+					Reason: gotext.GetD(cmd.client.language, "The match is paused."),
+				})
+				continue
 			}
 
 			opponent := clientGame.opponent(cmd.client)
@@ -969,7 +1068,7 @@ COMMANDS:
 			}
 
 			legalMoves := clientGame.LegalMoves(false)
-			if len(legalMoves) != 0 {
+			if len(legalMoves) != 0 && !clientGame.AllowUnusedDice {
 				available := bgammon.FlipMoves(legalMoves, cmd.client.playerNumber, clientGame.Variant)
 				bgammon.SortMoves(available)
 				cmd.client.sendEvent(&bgammon.EventFailedOk{
@@ -1052,6 +1151,12 @@ COMMANDS:
 				newGame.name = clientGame.name
 				newGame.Points = clientGame.Points
 				newGame.password = clientGame.password
+				newGame.NoUndo = clientGame.NoUndo
+				newGame.AllowUnusedDice = clientGame.AllowUnusedDice
+				newGame.AutoStart = clientGame.AutoStart
+				newGame.NoCube = clientGame.NoCube
+				newGame.MustFillLow = clientGame.MustFillLow
+				newGame.demo = clientGame.demo
 				newGame.client1 = clientGame.client1
 				newGame.client2 = clientGame.client2
 				newGame.spectators = make([]*serverClient, len(clientGame.spectators))
@@ -1060,8 +1165,12 @@ COMMANDS:
 				newGame.Player2.Name = clientGame.Player2.Name
 				newGame.Player1.Rating = clientGame.Player1.Rating
 				newGame.Player2.Rating = clientGame.Player2.Rating
+				newGame.Player1.Color = clientGame.Player1.Color
+				newGame.Player2.Color = clientGame.Player2.Color
 				newGame.allowed1 = clientGame.allowed1
 				newGame.allowed2 = clientGame.allowed2
+				newGame.account1 = clientGame.account1
+				newGame.account2 = clientGame.account2
 				s.games = append(s.games, newGame)
 
 				clientGame.client1 = nil
@@ -1105,6 +1214,7 @@ COMMANDS:
 				for _, spectator := range newGame.spectators {
 					newGame.sendBoard(spectator, false)
 				}
+				s.autoStart(newGame)
 			} else {
 				clientGame.rematch = cmd.client.playerNumber
 
@@ -1112,6 +1222,113 @@ COMMANDS:
 				cmd.client.sendNotice(gotext.GetD(cmd.client.language, "Rematch offer sent."))
 				continue
 			}
+		case bgammon.CommandDraw:
+			if clientGame == nil {
+				cmd.client.sendNotice(gotext.GetD(cmd.client.language, "You are not currently in a match."))
+				continue
+			} else if cmd.client != clientGame.client1 && cmd.client != clientGame.client2 {
+				cmd.client.sendNotice(gotext.GetD(cmd.client.language, "Only players may offer a draw."))
+				continue
+			} else if clientGame.Winner != 0 || clientGame.Turn == 0 {
+				cmd.client.sendNotice(gotext.GetD(cmd.client.language, "A draw may only be offered while a game is in progress."))
+				continue
+			} else if clientGame.rated() {
+				cmd.client.sendNotice(gotext.GetD(cmd.client.language, "Draws are not available in rated matches."))
+				continue
+			} else if clientGame.draw == cmd.client.playerNumber {
+				cmd.client.sendNotice(gotext.GetD(cmd.client.language, "You have already offered a draw."))
+				continue
+			}
+
+			clientGame.offerDraw(cmd.client)
+		case bgammon.CommandTakeback:
+			if clientGame == nil {
+				cmd.client.sendNotice(gotext.GetD(cmd.client.language, "You are not currently in a match."))
+				continue
+			} else if clientGame.rated() {
+				cmd.client.sendNotice(gotext.GetD(cmd.client.language, "Take-backs are not available in rated matches."))
+				continue
+			} else if clientGame.Winner != 0 || clientGame.Turn == 0 || clientGame.takeback == nil {
+				cmd.client.sendNotice(gotext.GetD(cmd.client.language, "There is no turn to take back."))
+				continue
+			} else if clientGame.paused {
+				cmd.client.sendNotice(gotext.GetD(cmd.client.language, "The match is paused."))
+				continue
+			} else if clientGame.takebackRequest == 0 && clientGame.takeback.Turn != cmd.client.playerNumber {
+				cmd.client.sendNotice(gotext.GetD(cmd.client.language, "Only the player who played the previous turn may request to take it back."))
+				continue
+			} else if clientGame.takebackRequest == cmd.client.playerNumber {
+				cmd.client.sendNotice(gotext.GetD(cmd.client.language, "You have already requested to take back your previous turn."))
+				continue
+			} else if clientGame.opponent(cmd.client) == nil {
+				cmd.client.sendNotice(gotext.GetD(cmd.client.language, "You must wait until your opponent rejoins the match."))
+				continue
+			}
+
+			clientGame.requestTakeback(cmd.client)
+		case bgammon.CommandUndoDecline:
+			if clientGame == nil {
+				cmd.client.sendNotice(gotext.GetD(cmd.client.language, "You are not currently in a match."))
+				continue
+			} else if clientGame.rated() {
+				cmd.client.sendNotice(gotext.GetD(cmd.client.language, "Declined double offers may not be undone in rated matches."))
+				continue
+			} else if clientGame.declined == nil {
+				cmd.client.sendNotice(gotext.GetD(cmd.client.language, "There is no declined double offer to undo."))
+				continue
+			} else if clientGame.declinedRequest == 0 && clientGame.declined.Turn == cmd.client.playerNumber {
+				cmd.client.sendNotice(gotext.GetD(cmd.client.language, "Only the player who declined the double offer may request to undo it."))
+				continue
+			} else if clientGame.declinedRequest == cmd.client.playerNumber {
+				cmd.client.sendNotice(gotext.GetD(cmd.client.language, "You have already requested to undo declining the double offer."))
+				continue
+			} else if clientGame.opponent(cmd.client) == nil {
+				cmd.client.sendNotice(gotext.GetD(cmd.client.language, "You must wait until your opponent rejoins the match."))
+				continue
+			}
+
+			clientGame.requestUndoDecline(cmd.client)
+		case bgammon.CommandPause, bgammon.CommandResume:
+			pause := keyword == bgammon.CommandPause
+			if clientGame == nil {
+				cmd.client.sendNotice(gotext.GetD(cmd.client.language, "You are not currently in a match."))
+				continue
+			} else if clientGame.Winner != 0 || clientGame.Turn == 0 {
+				cmd.client.sendNotice(gotext.GetD(cmd.client.language, "The match may only be paused while a game is in progress."))
+				continue
+			} else if pause && clientGame.paused {
+				cmd.client.sendNotice(gotext.GetD(cmd.client.language, "The match is already paused."))
+				continue
+			} else if !pause && !clientGame.paused {
+				cmd.client.sendNotice(gotext.GetD(cmd.client.language, "The match is not paused."))
+				continue
+			} else if pause && clientGame.pauseTotal >= pauseLimit {
+				cmd.client.sendNotice(gotext.GetD(cmd.client.language, "The match may not be paused again."))
+				continue
+			} else if clientGame.opponent(cmd.client) == nil {
+				cmd.client.sendNotice(gotext.GetD(cmd.client.language, "You must wait until your opponent rejoins the match."))
+				continue
+			}
+
+			clientGame.requestPause(cmd.client, pause)
+		case bgammon.CommandRematchToken:
+			if clientGame == nil {
+				cmd.client.sendNotice(gotext.GetD(cmd.client.language, "You are not currently in a match."))
+				continue
+			} else if clientGame.Winner == 0 {
+				cmd.client.sendNotice(gotext.GetD(cmd.client.language, "The match you are in is still in progress."))
+				continue
+			} else if (cmd.client != clientGame.client1 && cmd.client != clientGame.client2) || clientGame.allowed1 == nil {
+				cmd.client.sendNotice(gotext.GetD(cmd.client.language, "Only players may generate a rematch token."))
+				continue
+			}
+
+			token := s.newRematchToken(clientGame)
+			for _, client := range []*serverClient{clientGame.client1, clientGame.client2} {
+				if client != nil {
+					client.sendNotice(fmt.Sprintf(gotext.GetD(client.language, "Rematch token: %s (send 'join %s' to rematch)"), token, token))
+				}
+			}
 		case bgammon.CommandBoard, "b":
 			if clientGame == nil {
 				cmd.client.sendNotice(gotext.GetD(cmd.client.language, "You are not currently in a match."))
@@ -1147,6 +1364,28 @@ COMMANDS:
 			}
 
 			name := string(bytes.ToLower(params[0]))
+			if name == "color" {
+				color := string(params[1])
+				if bytes.Equal(bytes.ToLower(params[1]), clearBytes) {
+					color = ""
+				} else if !checkerColor.MatchString(color) {
+					cmd.client.sendNotice("Invalid setting value provided.")
+					continue
+				}
+				cmd.client.color = color
+
+				if clientGame != nil && (clientGame.client1 == cmd.client || clientGame.client2 == cmd.client) {
+					if cmd.client.playerNumber == 1 {
+						clientGame.Player1.Color = color
+					} else {
+						clientGame.Player2.Color = color
+					}
+					clientGame.eachClient(func(client *serverClient) {
+						clientGame.sendBoard(client, false)
+					})
+				}
+				continue
+			}
 			settings := []string{"autoplay", "highlight", "pips", "moves", "flip", "traditional", "advanced", "mutejoinleave", "mutechat", "muteroll", "mutemove", "mutebearoff", "speed"}
 			var found bool
 			for i := range settings {
@@ -1166,8 +1405,11 @@ COMMANDS:
 				continue
 			}
 
-			if name == "autoplay" {
+			switch name {
+			case "autoplay":
 				cmd.client.autoplay = value == 1
+			case "flip":
+				cmd.client.flip = value == 1
 			}
 
 			if cmd.client.account == nil {
@@ -1193,7 +1435,11 @@ COMMANDS:
 					cmd.client.sendNotice(gotext.GetD(cmd.client.language, "Invalid replay ID provided."))
 					continue
 				}
-				replay, err = replayByID(id)
+				var accountID int
+				if cmd.client.account != nil {
+					accountID = cmd.client.account.id
+				}
+				replay, err = replayByID(id, accountID)
 				if err != nil {
 					cmd.client.sendNotice(gotext.GetD(cmd.client.language, "Invalid replay ID provided."))
 					continue
@@ -1294,6 +1540,88 @@ COMMANDS:
 				sc.sendBroadcast(message)
 			}
 			s.clientsLock.Unlock()
+		case bgammon.CommandDump:
+			if !cmd.client.Admin() {
+				cmd.client.sendNotice("Access denied.")
+				continue
+			}
+
+			g := clientGame
+			if len(params) > 0 {
+				id, err := strconv.Atoi(string(params[0]))
+				if err != nil {
+					cmd.client.sendNotice("Invalid match ID provided.")
+					continue
+				}
+				g = nil
+				s.gamesLock.RLock()
+				for _, sg := range s.games {
+					if sg.id == id {
+						g = sg
+						break
+					}
+				}
+				s.gamesLock.RUnlock()
+			}
+			if g == nil {
+				cmd.client.sendNotice("Match not found.")
+				continue
+			}
+
+			buf, err := json.Marshal(g.snapshot())
+			if err != nil {
+				cmd.client.sendNotice(fmt.Sprintf("Failed to dump match: %s", err))
+				continue
+			}
+			cmd.client.sendNotice(string(buf))
+		case bgammon.CommandRestore:
+			if !cmd.client.Admin() {
+				cmd.client.sendNotice("Access denied.")
+				continue
+			} else if clientGame != nil {
+				cmd.client.sendNotice("Please leave your current match before restoring a match.")
+				continue
+			} else if len(params) == 0 {
+				cmd.client.sendNotice("Please specify the match state as follows: restore <json>")
+				continue
+			}
+
+			snapshot := &gameSnapshot{}
+			err := json.Unmarshal(bytes.Join(params, []byte(" ")), snapshot)
+			if err != nil || snapshot.Game == nil || len(snapshot.Game.Board) != bgammon.BoardSpaces {
+				cmd.client.sendNotice("Invalid match state provided.")
+				continue
+			}
+
+			s.gamesLock.Lock()
+			g := newServerGame(<-s.newGameIDs, snapshot.Game.Variant)
+			g.restore(snapshot)
+			s.games = append(s.games, g)
+			s.gamesLock.Unlock()
+
+			g.addClient(cmd.client)
+			cmd.client.sendNotice(fmt.Sprintf("Restored match %d as match %d.", snapshot.ID, g.id))
+		case bgammon.CommandDemo:
+			if !cmd.client.Admin() {
+				cmd.client.sendNotice("Access denied.")
+				continue
+			} else if clientGame != nil {
+				cmd.client.sendNotice("Please leave your current match before starting a demo match.")
+				continue
+			}
+
+			levels := [2]int{1, 1}
+			for i := 0; i < len(params) && i < len(levels); i++ {
+				level, err := strconv.Atoi(string(params[i]))
+				if err != nil || level < 0 {
+					cmd.client.sendNotice("Please specify the bot levels as follows: demo [level1] [level2]")
+					continue COMMANDS
+				}
+				levels[i] = level
+			}
+
+			g := s.startDemo(levels[0], levels[1], cmd.client)
+			cmd.client.sendNotice(fmt.Sprintf("Started demo match %d.", g.id))
 		case bgammon.CommandShutdown:
 			if !cmd.client.Admin() {
 				cmd.client.sendNotice("Access denied.")
@@ -0,0 +1,196 @@
+package server
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"code.rocket9labs.com/tslocum/bgammon"
+)
+
+// testClient records the messages sent to it.
+type testClient struct {
+	messages   chan []byte
+	terminated bool
+}
+
+func (c *testClient) HandleReadWrite() {}
+
+func (c *testClient) Write(message []byte) {
+	select {
+	case c.messages <- message:
+	default:
+	}
+}
+
+func (c *testClient) Terminate(reason string) {
+	c.terminated = true
+}
+
+func (c *testClient) Terminated() bool {
+	return c.terminated
+}
+
+// newTestClient returns a client with the provided username.
+func newTestClient(id int, name string) *serverClient {
+	return &serverClient{
+		id:       id,
+		name:     []byte(name),
+		language: "en",
+		commands: make(chan []byte, 10),
+		Client:   &testClient{messages: make(chan []byte, 100)},
+	}
+}
+
+// waitForMessage waits for the client to receive the provided message.
+func waitForMessage(t *testing.T, client *serverClient, message string) {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case m := <-client.Client.(*testClient).messages:
+			if bytes.Equal(m, []byte(message)) {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for %s to receive message: %s", client.name, message)
+		}
+	}
+}
+
+// newTestAccount returns an account with the provided ID and username.
+func newTestAccount(id int, username string) *account {
+	return &account{
+		id:          id,
+		username:    []byte(username),
+		casual:      &clientRating{},
+		competitive: &clientRating{},
+	}
+}
+
+// newTestServer returns a server without a database.
+func newTestServer() *server {
+	return NewServer("", "", "", "", "", false, false, false)
+}
+
+// joinedGame returns the match the provided client is playing.
+func joinedGame(s *server, client *serverClient) *serverGame {
+	s.gamesLock.RLock()
+	defer s.gamesLock.RUnlock()
+	for _, g := range s.games {
+		if g.client1 == client || g.client2 == client {
+			return g
+		}
+	}
+	return nil
+}
+
+func TestRematchTokenSettings(t *testing.T) {
+	s := newTestServer()
+	s.SetNoUndo(false)
+	s.SetAllowUnusedDice(false)
+
+	finished := newServerGame(<-s.newGameIDs, bgammon.VariantBackgammon)
+	finished.name = []byte("Test match")
+	finished.Points = 3
+	finished.allowed1, finished.allowed2 = []byte("alice"), []byte("bob")
+	finished.NoUndo = true
+	finished.AllowUnusedDice = true
+	finished.AutoStart = true
+	finished.NoCube = true
+	finished.MustFillLow = true
+	token := s.newRematchToken(finished)
+
+	alice := newTestClient(1, "alice")
+	s.joinRematch(alice, token)
+	g := joinedGame(s, alice)
+	if g == nil {
+		t.Fatal("expected rematch to be created")
+	} else if g.Points != 3 {
+		t.Errorf("expected rematch to be played to 3 points, got %d", g.Points)
+	} else if !g.NoUndo || !g.AllowUnusedDice || !g.AutoStart || !g.NoCube || !g.MustFillLow {
+		t.Errorf("expected rematch to keep the settings of the finished match, got NoUndo=%v AllowUnusedDice=%v AutoStart=%v NoCube=%v MustFillLow=%v", g.NoUndo, g.AllowUnusedDice, g.AutoStart, g.NoCube, g.MustFillLow)
+	}
+}
+
+func TestRenamedPlayerRejoin(t *testing.T) {
+	s := newTestServer()
+
+	finished := newServerGame(<-s.newGameIDs, bgammon.VariantBackgammon)
+	finished.name = []byte("Test match")
+	finished.Points = 1
+	finished.allowed1, finished.allowed2 = []byte("alice"), []byte("bob")
+	finished.account1, finished.account2 = 1001, 1002
+	token := s.newRematchToken(finished)
+
+	stranger := newTestClient(1, "carol")
+	stranger.account = newTestAccount(1003, "carol")
+	s.joinRematch(stranger, token)
+	if joinedGame(s, stranger) != nil {
+		t.Fatal("expected a player who was not in the finished match to be refused")
+	}
+
+	alice := newTestClient(2, "alice_renamed")
+	alice.account = newTestAccount(1001, "alice_renamed")
+	s.joinRematch(alice, token)
+	g := joinedGame(s, alice)
+	if g == nil {
+		t.Fatal("expected a renamed player to be allowed to join using the rematch token")
+	}
+
+	if listing := g.listing(stranger); listing == nil || listing.Players != 2 {
+		t.Errorf("expected the match to be listed as full for other players, got %+v", listing)
+	}
+	bob := newTestClient(3, "bob_renamed")
+	bob.account = newTestAccount(1002, "bob_renamed")
+	if listing := g.listing(bob); listing == nil || listing.Players != 1 {
+		t.Errorf("expected the match to be listed as awaiting a renamed player, got %+v", listing)
+	}
+
+	s.joinRematch(bob, token)
+	if joinedGame(s, bob) != g {
+		t.Fatal("expected a renamed player to join the rematch created by their opponent")
+	}
+}
+
+func TestTakebackAfterWin(t *testing.T) {
+	s := newTestServer()
+
+	g := newServerGame(<-s.newGameIDs, bgammon.VariantBackgammon)
+	g.Points = 3
+	alice, bob := newTestClient(1, "alice"), newTestClient(2, "bob")
+	g.addClient(alice)
+	g.addClient(bob)
+	winner, loser := g.client1, g.client2
+
+	board := make([]int8, bgammon.BoardSpaces)
+	board[1], board[bgammon.SpaceHomePlayer] = 1, 14
+	board[20], board[bgammon.SpaceHomeOpponent] = -5, -10
+	copy(g.Board, board)
+	g.Turn, g.Roll1, g.Roll2 = 1, 6, 5
+	g.Started = time.Now()
+
+	// The loser played the previous turn.
+	g.takeback = g.Copy(false)
+	g.takeback.Turn = 2
+	g.replay = append(g.replay, []byte("2 r 6-5 24/18 24/19"))
+	g.takebackReplay = len(g.replay)
+
+	s.gamesLock.Lock()
+	s.games = append(s.games, g)
+	s.gamesLock.Unlock()
+
+	s.commands <- serverCommand{client: winner, command: []byte("move 1/off")}
+	s.commands <- serverCommand{client: loser, command: []byte(bgammon.CommandTakeback)}
+	waitForMessage(t, loser, "notice There is no turn to take back.")
+
+	s.gamesLock.RLock()
+	defer s.gamesLock.RUnlock()
+	if g.Player1.Points != 1 || g.Turn != 0 || g.Winner != 0 {
+		t.Fatalf("expected the next game of the match to be awaiting the opening roll with player 1 leading 1-0, got turn %d, winner %d and score %d-%d", g.Turn, g.Winner, g.Player1.Points, g.Player2.Points)
+	} else if g.takeback != nil || g.takebackRequest != 0 {
+		t.Fatal("expected the take-back state of the finished game to be cleared")
+	} else if len(g.replay) != 0 {
+		t.Fatalf("expected the replay of the finished game to be cleared, got %d lines", len(g.replay))
+	}
+}
@@ -5,8 +5,9 @@ type Player struct {
 	Name     string
 	Rating   int
 	Points   int8
-	Entered  bool // Whether all checkers have entered the board. (Acey-deucey)
-	Inactive int  // Inactive time. (Seconds)
+	Entered  bool   // Whether all checkers have entered the board. (Acey-deucey)
+	Inactive int    // Inactive time. (Seconds)
+	Color    string // Checker color or symbol chosen by the player. Clients use their default when empty.
 }
 
 func NewPlayer(number int8) Player {
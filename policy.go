@@ -0,0 +1,109 @@
+package bgammon
+
+import (
+	"math/rand"
+)
+
+// MovePolicy chooses the sequence of moves played by the current player using
+// the remaining dice. Policies are used by bots and simulated games. When local
+// is true, moves are returned from the perspective of the current player.
+type MovePolicy interface {
+	ChooseMoves(g *Game, local bool) [][]int8
+}
+
+// RandomPolicy chooses a random legal sequence of moves.
+type RandomPolicy struct {
+	Rand *rand.Rand // Source of randomness. When nil, the default source is used.
+}
+
+func (p RandomPolicy) ChooseMoves(g *Game, local bool) [][]int8 {
+	sequences := g.sequences()
+	if len(sequences) == 0 {
+		return nil
+	}
+	var i int
+	if p.Rand != nil {
+		i = p.Rand.Intn(len(sequences))
+	} else {
+		i = rand.Intn(len(sequences))
+	}
+	moves := sequenceMoves(sequences[i])
+	if local {
+		moves = FlipMoves(moves, g.Turn, g.Variant)
+	}
+	return moves
+}
+
+// HeuristicPolicy chooses the sequence of moves which results in the position
+// with the best rough evaluation, preferring made points and avoiding blots.
+type HeuristicPolicy struct{}
+
+func (p HeuristicPolicy) ChooseMoves(g *Game, local bool) [][]int8 {
+	moves := g.bestSequence(func(gc *Game) float64 {
+		return heuristicScore(gc, g.Turn)
+	})
+	if local {
+		moves = FlipMoves(moves, g.Turn, g.Variant)
+	}
+	return moves
+}
+
+// BestBearOffPolicy chooses the sequence of moves which minimizes the expected
+// number of rolls needed to bear off when the player is bearing off in a
+// backgammon game without contact. Other positions are played using
+// HeuristicPolicy.
+type BestBearOffPolicy struct{}
+
+func (p BestBearOffPolicy) ChooseMoves(g *Game, local bool) [][]int8 {
+	if g.Variant != VariantBackgammon || !g.IsRace() || !g.MayBearOff(g.Turn, false) {
+		return HeuristicPolicy{}.ChooseMoves(g, local)
+	}
+	moves := g.bestSequence(func(gc *Game) float64 {
+		return -bearOffExpected(gc.homeCheckers(g.Turn))
+	})
+	if local {
+		moves = FlipMoves(moves, g.Turn, g.Variant)
+	}
+	return moves
+}
+
+// BotMove returns the moves chosen by the provided policy for the current
+// player. HeuristicPolicy is used when no policy is provided.
+func BotMove(g *Game, local bool, policy MovePolicy) [][]int8 {
+	if g.Turn == 0 || g.Roll1 == 0 || g.Winner != 0 {
+		return nil
+	}
+	if policy == nil {
+		policy = HeuristicPolicy{}
+	}
+	return policy.ChooseMoves(g, local)
+}
+
+// bestSequence returns the legal sequence of moves which results in the
+// position with the highest score.
+func (g *Game) bestSequence(score func(gc *Game) float64) [][]int8 {
+	var best [][2]int8
+	var bestScore float64
+	for i, moves := range g.sequences() {
+		gc := g.Copy(true)
+		for _, move := range moves {
+			gc.addMove(move[:])
+		}
+		s := score(gc)
+		if i == 0 || s > bestScore {
+			best, bestScore = moves, s
+		}
+	}
+	return sequenceMoves(best)
+}
+
+func sequenceMoves(sequence [][2]int8) [][]int8 {
+	if len(sequence) == 0 {
+		return nil
+	}
+	moves := make([][]int8, len(sequence))
+	for i := range sequence {
+		moves[i] = []int8{sequence[i][0], sequence[i][1]}
+	}
+	return moves
+}
@@ -0,0 +1,23 @@
+package bgammon
+
+import (
+	"testing"
+)
+
+func TestBotMoveLocal(t *testing.T) {
+	board := make([]int8, BoardSpaces)
+	board[22], board[SpaceHomeOpponent] = -1, -14
+	board[1] = 15
+
+	g := newTestGame(VariantBackgammon, board, 2, 3, 3)
+	for _, policy := range []MovePolicy{nil, RandomPolicy{}, HeuristicPolicy{}, BestBearOffPolicy{}} {
+		absolute := BotMove(g, false, policy)
+		if len(absolute) != 1 || absolute[0][0] != 22 || absolute[0][1] != SpaceHomeOpponent {
+			t.Fatalf("%T: expected 22/off, got %s", policy, FormatMoves(absolute))
+		}
+		local := BotMove(g, true, policy)
+		if len(local) != 1 || local[0][0] != 3 || local[0][1] != SpaceHomePlayer {
+			t.Fatalf("%T: expected 3/off from the perspective of player 2, got %s", policy, FormatMoves(local))
+		}
+	}
+}
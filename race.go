@@ -0,0 +1,74 @@
+package bgammon
+
+import "math"
+
+// DecidedRaceChance is the winning chance below which a race is considered
+// decided by IsDecided.
+var DecidedRaceChance = 0.005
+
+// Mean and variance of the number of pips moved by a single roll.
+const (
+	rollPipsMean     = 49.0 / 6.0
+	rollPipsVariance = 3066.0/36.0 - rollPipsMean*rollPipsMean
+)
+
+// raceWinChance returns an estimate of the chance that the player on roll
+// wins a race, given the pip counts of both players. The number of rolls each
+// player needs is approximated by a normal distribution.
+func raceWinChance(pips int, opponentPips int) float64 {
+	if pips <= 0 {
+		return 1
+	} else if opponentPips <= 0 {
+		return 0
+	}
+	mean := float64(opponentPips-pips) / rollPipsMean
+	variance := float64(pips+opponentPips) * rollPipsVariance / (rollPipsMean * rollPipsMean * rollPipsMean)
+	// The player on roll wins ties in the number of rolls needed.
+	return 0.5 * math.Erfc(-(mean+0.5)/math.Sqrt(2*variance))
+}
+
+// IsDecided returns whether the game is a race in which either player has
+// effectively no chance of winning. Local is ignored, as the result is the
+// same for both players.
+func (g *Game) IsDecided(local bool) bool {
+	if g.Winner != 0 {
+		return true
+	} else if g.Turn == 0 || !g.IsRace() {
+		return false
+	}
+	var opponent int8 = 1
+	if g.Turn == 1 {
+		opponent = 2
+	}
+	chance := raceWinChance(g.PipCount(g.Turn), g.PipCount(opponent))
+	return chance < DecidedRaceChance || 1-chance < DecidedRaceChance
+}
+
+// KeithCount returns the provided player's pip count adjusted for wastage
+// using the Keith count. The pip count is increased by 2 for each checker
+// beyond the first on the 1 point, by 1 for each checker beyond the first on
+// the 2 point, by 1 for each checker beyond the third on the 3 point, and by 1
+// for each empty point among the 4, 5 and 6 points. When deciding whether to
+// double, the count of the player on roll is typically increased by a further
+// seventh.
+func (g *Game) KeithCount(player int8) int {
+	count := g.PipCount(player)
+	checkers := func(point int8) int {
+		return int(PlayerCheckers(g.Board[playerSpace(point, player, g.Variant)], player))
+	}
+	if c := checkers(1); c > 1 {
+		count += 2 * (c - 1)
+	}
+	if c := checkers(2); c > 1 {
+		count += c - 1
+	}
+	if c := checkers(3); c > 3 {
+		count += c - 3
+	}
+	for point := int8(4); point <= 6; point++ {
+		if checkers(point) == 0 {
+			count++
+		}
+	}
+	return count
+}
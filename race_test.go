@@ -0,0 +1,31 @@
+package bgammon
+
+import (
+	"testing"
+)
+
+func TestKeithCount(t *testing.T) {
+	testCases := []struct {
+		player int8
+		points map[int8]int8
+		want   int
+	}{
+		// No wastage: the count equals the pip count of 63.
+		{1, map[int8]int8{6: 4, 5: 3, 4: 3, 3: 3, 2: 1, 1: 1}, 63},
+		{2, map[int8]int8{6: 4, 5: 3, 4: 3, 3: 3, 2: 1, 1: 1}, 63},
+		// Pip count of 55, plus 4 for the 1 point, 1 for the 2 point, 1 for the
+		// 3 point and 1 each for the empty 4 and 5 points.
+		{1, map[int8]int8{6: 6, 3: 4, 2: 2, 1: 3}, 63},
+		{2, map[int8]int8{6: 6, 3: 4, 2: 2, 1: 3}, 63},
+		// Pip count of 95, plus 1 for the empty 4 point.
+		{1, map[int8]int8{8: 5, 6: 5, 5: 5}, 96},
+		{2, map[int8]int8{8: 5, 6: 5, 5: 5}, 96},
+	}
+	for _, c := range testCases {
+		board := playerBoard(c.player, VariantBackgammon, c.points)
+		g := newTestGame(VariantBackgammon, board, c.player, 0, 0)
+		if count := g.KeithCount(c.player); count != c.want {
+			t.Errorf("player %d %v: expected Keith count of %d, got %d", c.player, c.points, c.want, count)
+		}
+	}
+}
@@ -0,0 +1,345 @@
+package bgammon
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+)
+
+const (
+	ReplayEventRoll   byte = 'r' // Dice were rolled and moves were played.
+	ReplayEventDouble byte = 'd' // A double was offered and accepted or declined.
+	ReplayEventResign byte = 't' // A player resigned or forfeited.
+
+	// ReplayEventKeyframe records the state of the game at the end of a turn,
+	// allowing a replay to be seeked without replaying every prior event. The
+	// player of a keyframe is the player whose turn is next. Keyframes are
+	// optional.
+	ReplayEventKeyframe byte = 'k'
+)
+
+// Replay is a recorded match.
+type Replay struct {
+	Games []*ReplayGame
+}
+
+// ReplayGame is a single recorded game within a match.
+type ReplayGame struct {
+	Timestamp   int64
+	Player1     string
+	Player2     string
+	Points      int8 // Points required to win the match.
+	Score1      int8
+	Score2      int8
+	Winner      int8
+	DoubleValue int8
+	Variant     int8
+	Color1      string // Checker color or symbol chosen by player 1. Empty when the default color was used.
+	Color2      string // Checker color or symbol chosen by player 2. Empty when the default color was used.
+	Events      []*ReplayEvent
+}
+
+// ReplayEvent is a single recorded event. Spaces are numbered from the
+// perspective of player 1.
+type ReplayEvent struct {
+	Player   int8
+	Type     byte
+	Roll     [3]int8
+	Moves    [][]int8
+	Value    int8 // Doubling cube value offered, or the doubling cube value recorded by a keyframe.
+	Accepted bool // Whether the double was accepted.
+
+	Board        []int8  // Board state recorded by a keyframe.
+	DoublePlayer int8    // Player possessing the doubling cube recorded by a keyframe.
+	Entered      [2]bool // Whether each player has entered all of their checkers, recorded by a keyframe.
+}
+
+// DecodeReplay parses a replay as recorded by the server.
+func DecodeReplay(replay []byte) (*Replay, error) {
+	r := &Replay{}
+	var game *ReplayGame
+	for i, line := range bytes.Split(replay, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 || bytes.HasPrefix(line, []byte("bgammon-replay")) {
+			continue
+		}
+
+		fields := bytes.Fields(line)
+		if bytes.Equal(fields[0], []byte("i")) {
+			var err error
+			game, err = decodeReplayHeader(fields[1:])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %s", i+1, err)
+			}
+			r.Games = append(r.Games, game)
+			continue
+		} else if game == nil {
+			return nil, fmt.Errorf("line %d: event recorded before game header", i+1)
+		}
+
+		ev, err := decodeReplayEvent(fields)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", i+1, err)
+		}
+		game.Events = append(game.Events, ev)
+	}
+	return r, nil
+}
+
+func decodeReplayHeader(fields [][]byte) (*ReplayGame, error) {
+	if len(fields) < 8 {
+		return nil, fmt.Errorf("invalid game header")
+	}
+	timestamp, err := strconv.ParseInt(string(fields[0]), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid game timestamp")
+	}
+	var values [6]int8
+	for i := range values {
+		if i+3 >= len(fields) {
+			break
+		}
+		v, err := strconv.Atoi(string(fields[i+3]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid game header")
+		}
+		values[i] = int8(v)
+	}
+	var colors [2]string
+	for i := range colors {
+		if i+9 < len(fields) && !bytes.Equal(fields[i+9], []byte("clear")) {
+			colors[i] = string(fields[i+9])
+		}
+	}
+	return &ReplayGame{
+		Timestamp:   timestamp,
+		Player1:     string(fields[1]),
+		Player2:     string(fields[2]),
+		Points:      values[0],
+		Score1:      values[1],
+		Score2:      values[2],
+		Winner:      values[3],
+		DoubleValue: values[4],
+		Variant:     values[5],
+		Color1:      colors[0],
+		Color2:      colors[1],
+	}, nil
+}
+
+func decodeReplayEvent(fields [][]byte) (*ReplayEvent, error) {
+	if len(fields) < 2 || len(fields[1]) != 1 {
+		return nil, fmt.Errorf("invalid event")
+	}
+	player, err := strconv.Atoi(string(fields[0]))
+	if err != nil || player < 1 || player > 2 {
+		return nil, fmt.Errorf("invalid player")
+	}
+	ev := &ReplayEvent{
+		Player: int8(player),
+		Type:   fields[1][0],
+	}
+	switch ev.Type {
+	case ReplayEventRoll:
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("missing roll")
+		}
+		rolls := bytes.Split(fields[2], []byte("-"))
+		if len(rolls) < 2 || len(rolls) > 3 {
+			return nil, fmt.Errorf("invalid roll: %s", fields[2])
+		}
+		for i := range rolls {
+			roll, err := strconv.Atoi(string(rolls[i]))
+			if err != nil || roll < 1 || roll > 6 {
+				return nil, fmt.Errorf("invalid roll: %s", fields[2])
+			}
+			ev.Roll[i] = int8(roll)
+		}
+		for _, field := range fields[3:] {
+			spaces := bytes.Split(field, []byte("/"))
+			if len(spaces) != 2 {
+				return nil, fmt.Errorf("invalid move: %s", field)
+			}
+			move := []int8{ParseSpace(string(spaces[0])), ParseSpace(string(spaces[1]))}
+			for i := range move {
+				if !ValidSpace(move[i]) {
+					return nil, fmt.Errorf("invalid move: %s", field)
+				}
+				// Moves are recorded without distinguishing which player's bar or home is used.
+				if ev.Player == 2 {
+					if move[i] == SpaceBarPlayer {
+						move[i] = SpaceBarOpponent
+					} else if move[i] == SpaceHomePlayer {
+						move[i] = SpaceHomeOpponent
+					}
+				}
+			}
+			ev.Moves = append(ev.Moves, move)
+		}
+	case ReplayEventDouble:
+		if len(fields) < 4 {
+			return nil, fmt.Errorf("invalid double")
+		}
+		value, err := strconv.Atoi(string(fields[2]))
+		if err != nil || value < 2 {
+			return nil, fmt.Errorf("invalid double value: %s", fields[2])
+		}
+		ev.Value = int8(value)
+		ev.Accepted = bytes.Equal(fields[3], []byte("1"))
+	case ReplayEventResign:
+	case ReplayEventKeyframe:
+		if len(fields) < 6 {
+			return nil, fmt.Errorf("invalid keyframe")
+		}
+		spaces := bytes.Split(fields[2], []byte(","))
+		if len(spaces) != BoardSpaces {
+			return nil, fmt.Errorf("invalid keyframe board: %s", fields[2])
+		}
+		ev.Board = make([]int8, BoardSpaces)
+		for i := range spaces {
+			v, err := strconv.Atoi(string(spaces[i]))
+			if err != nil || v < -15 || v > 15 {
+				return nil, fmt.Errorf("invalid keyframe board: %s", fields[2])
+			}
+			ev.Board[i] = int8(v)
+		}
+		value, err := strconv.Atoi(string(fields[3]))
+		if err != nil || value < 1 {
+			return nil, fmt.Errorf("invalid keyframe double value: %s", fields[3])
+		}
+		ev.Value = int8(value)
+		doublePlayer, err := strconv.Atoi(string(fields[4]))
+		if err != nil || doublePlayer < 0 || doublePlayer > 2 {
+			return nil, fmt.Errorf("invalid keyframe double player: %s", fields[4])
+		}
+		ev.DoublePlayer = int8(doublePlayer)
+		if len(fields[5]) != 2 {
+			return nil, fmt.Errorf("invalid keyframe entered state: %s", fields[5])
+		}
+		ev.Entered = [2]bool{fields[5][0] == '1', fields[5][1] == '1'}
+	default:
+		return nil, fmt.Errorf("unknown event type: %c", ev.Type)
+	}
+	return ev, nil
+}
+
+// Verify replays each game and returns an error describing the first event
+// which could not have occurred in a legal game.
+func (r *Replay) Verify() error {
+	for i, game := range r.Games {
+		err := game.Verify()
+		if err != nil {
+			return fmt.Errorf("game %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// Verify replays the game and returns an error describing the first event
+// which could not have occurred in a legal game. Keyframes are compared with
+// the replayed state of the game.
+func (r *ReplayGame) Verify() error {
+	g := r.newGame()
+	for i, ev := range r.Events {
+		err := applyReplayEvent(g, ev, true)
+		if err != nil {
+			return fmt.Errorf("event %d: %s", i+1, err)
+		}
+	}
+	if r.Winner != 0 && g.Winner != r.Winner {
+		return fmt.Errorf("recorded winner %d does not match replayed winner %d", r.Winner, g.Winner)
+	}
+	return nil
+}
+
+// Apply returns the state of the game after the provided number of events
+// have occurred. Replaying starts from the nearest preceding keyframe, or from
+// the start of the game when the replay does not contain keyframes.
+func (r *ReplayGame) Apply(step int) (*Game, error) {
+	if step < 0 || step > len(r.Events) {
+		return nil, fmt.Errorf("invalid step %d: replay contains %d events", step, len(r.Events))
+	}
+	g := r.newGame()
+	var start int
+	for i := step - 1; i >= 0; i-- {
+		if r.Events[i].Type == ReplayEventKeyframe {
+			start = i
+			break
+		}
+	}
+	for i := start; i < step; i++ {
+		err := applyReplayEvent(g, r.Events[i], false)
+		if err != nil {
+			return nil, fmt.Errorf("event %d: %s", i+1, err)
+		}
+	}
+	return g, nil
+}
+
+func (r *ReplayGame) newGame() *Game {
+	g := NewGame(r.Variant)
+	g.Player1.Name, g.Player2.Name = r.Player1, r.Player2
+	g.Player1.Points, g.Player2.Points = r.Score1, r.Score2
+	g.Points = r.Points
+	return g
+}
+
+// applyReplayEvent applies the provided event to the game. When verify is
+// true, keyframes are compared with the game instead of being applied.
+func applyReplayEvent(g *Game, ev *ReplayEvent, verify bool) error {
+	if g.Winner != 0 {
+		return fmt.Errorf("recorded after the game ended")
+	}
+	opponent := opponentNumber(ev.Player)
+	switch ev.Type {
+	case ReplayEventRoll:
+		// The player who won the opening roll moves first.
+		if g.Turn == 0 {
+			g.Turn = ev.Player
+		} else if ev.Player != g.Turn {
+			return fmt.Errorf("rolled out of turn")
+		}
+		g.Roll1, g.Roll2, g.Roll3 = ev.Roll[0], ev.Roll[1], ev.Roll[2]
+		for _, move := range ev.Moves {
+			ok, _ := g.AddMoves([][]int8{move}, false)
+			if !ok {
+				return fmt.Errorf("illegal move %s", FormatMoves([][]int8{move}))
+			}
+		}
+		if g.Winner == 0 && len(g.LegalMoves(false)) != 0 {
+			return fmt.Errorf("legal moves were left unplayed")
+		}
+		// Playing an acey-deucey is followed by the chosen doubles and then by
+		// another roll, all by the same player.
+		aceyDeucey := g.Variant == VariantAceyDeucey && ((g.Roll1 == 1 && g.Roll2 == 2) || (g.Roll1 == 2 && g.Roll2 == 1)) && len(g.Moves) == 2
+		reroll := aceyDeucey || (g.Variant == VariantAceyDeucey && g.Reroll)
+		g.Reroll = aceyDeucey
+		g.NextTurn(reroll)
+	case ReplayEventDouble:
+		if g.DoublePlayer != 0 && g.DoublePlayer != ev.Player {
+			return fmt.Errorf("double offered without possessing the cube")
+		}
+		if ev.Accepted {
+			g.DoubleValue, g.DoublePlayer = ev.Value, opponent
+		} else {
+			g.Winner = ev.Player
+		}
+	case ReplayEventResign:
+		g.Winner = opponent
+	case ReplayEventKeyframe:
+		if verify {
+			for space := range ev.Board {
+				if g.Board[space] != ev.Board[space] {
+					return fmt.Errorf("keyframe does not match replayed board")
+				}
+			}
+			return nil
+		}
+		g.Board = make([]int8, BoardSpaces)
+		copy(g.Board, ev.Board)
+		g.Turn = ev.Player
+		g.Roll1, g.Roll2, g.Roll3 = 0, 0, 0
+		g.DoubleValue, g.DoublePlayer = ev.Value, ev.DoublePlayer
+		g.Player1.Entered, g.Player2.Entered = ev.Entered[0], ev.Entered[1]
+	}
+	return nil
+}
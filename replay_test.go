@@ -0,0 +1,109 @@
+package bgammon
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// formatKeyframe returns a keyframe event recording the provided board.
+func formatKeyframe(player int8, board []int8) string {
+	spaces := make([]string, len(board))
+	for i, v := range board {
+		spaces[i] = strconv.Itoa(int(v))
+	}
+	return strconv.Itoa(int(player)) + " k " + strings.Join(spaces, ",") + " 1 0 11"
+}
+
+func TestReplay(t *testing.T) {
+	const header = "i 1700000000 alice bob 1 0 0 0 1 0"
+	const roll1, roll2 = "1 r 3-1 8/5 6/5", "2 r 6-4 1/7 12/16"
+
+	board := NewBoard(VariantBackgammon)
+	board[8], board[6], board[5] = 2, 4, 2
+	board[1], board[7], board[12], board[16] = -1, -1, -4, -1
+
+	testCases := []struct {
+		name   string
+		events []string
+		err    string // Expected verification error. Empty when the replay is legal.
+	}{
+		{name: "legal", events: []string{roll1, roll2, formatKeyframe(1, board), "1 r 5-2 13/8 13/11"}},
+		{name: "tampered move", events: []string{"1 r 3-1 8/2 6/5", roll2}, err: "event 1: illegal move 8/2"},
+		{name: "out of turn", events: []string{roll1, "1 r 6-4 13/7 13/9"}, err: "event 2: rolled out of turn"},
+		{name: "opponent's turn", events: []string{roll1, "1 r 6-4 1/7 12/16"}, err: "event 2: rolled out of turn"},
+		{name: "keyframe mismatch", events: []string{roll1, roll2, formatKeyframe(1, NewBoard(VariantBackgammon))}, err: "event 3: keyframe does not match replayed board"},
+	}
+	for _, tc := range testCases {
+		r, err := DecodeReplay([]byte(header + "\n" + strings.Join(tc.events, "\n")))
+		if err != nil {
+			t.Errorf("%s: failed to decode replay: %s", tc.name, err)
+			continue
+		} else if len(r.Games) != 1 || len(r.Games[0].Events) != len(tc.events) {
+			t.Errorf("%s: expected 1 game with %d events", tc.name, len(tc.events))
+			continue
+		}
+		err = r.Verify()
+		if tc.err == "" && err != nil {
+			t.Errorf("%s: expected replay to be verified, got %s", tc.name, err)
+		} else if tc.err != "" && (err == nil || !strings.Contains(err.Error(), tc.err)) {
+			t.Errorf("%s: expected verification error %q, got %v", tc.name, tc.err, err)
+		}
+	}
+
+	r, err := DecodeReplay([]byte(header + "\n" + strings.Join(testCases[0].events, "\n")))
+	if err != nil {
+		t.Fatalf("failed to decode replay: %s", err)
+	}
+	game := r.Games[0]
+	for _, step := range []int{2, 3} {
+		g, err := game.Apply(step)
+		if err != nil {
+			t.Fatalf("step %d: failed to apply replay: %s", step, err)
+		}
+		for space := range board {
+			if g.Board[space] != board[space] {
+				t.Fatalf("step %d: expected board %v, got %v", step, board, g.Board)
+			}
+		}
+		if g.Turn != 1 {
+			t.Fatalf("step %d: expected player 1 to be on roll, got %d", step, g.Turn)
+		}
+	}
+	g, err := game.Apply(4)
+	if err != nil {
+		t.Fatalf("failed to apply replay from keyframe: %s", err)
+	} else if g.Board[13] != 3 || g.Board[11] != 1 || g.Turn != 2 {
+		t.Fatalf("expected the move after the keyframe to be applied, got %d on 13, %d on 11 and turn %d", g.Board[13], g.Board[11], g.Turn)
+	}
+	if _, err := game.Apply(5); err == nil {
+		t.Fatal("expected an error when applying more events than recorded")
+	}
+
+	// Playing an acey-deucey allows the same player to play doubles and then
+	// to roll again.
+	aceyDeucey := []string{
+		"i 1700000000 alice bob 1 0 0 0 1 1",
+		"1 r 2-1 off/24 off/23",
+		"1 r 6-6 off/19 off/19 off/19 off/19",
+		"1 r 4-3 off/21 off/22",
+		"2 r 4-3 off/4 off/3",
+	}
+	r, err = DecodeReplay([]byte(strings.Join(aceyDeucey, "\n")))
+	if err != nil {
+		t.Fatalf("failed to decode acey-deucey replay: %s", err)
+	} else if err = r.Verify(); err != nil {
+		t.Fatalf("expected acey-deucey replay to be verified, got %s", err)
+	}
+	aceyDeucey[4] = "1 r 4-3 off/21 off/22"
+	r, err = DecodeReplay([]byte(strings.Join(aceyDeucey, "\n")))
+	if err != nil {
+		t.Fatalf("failed to decode acey-deucey replay: %s", err)
+	} else if err = r.Verify(); err == nil || !strings.Contains(err.Error(), "event 4: rolled out of turn") {
+		t.Fatalf("expected a fourth roll by the same player to be out of turn, got %v", err)
+	}
+
+	if _, err := DecodeReplay([]byte(roll1)); err == nil {
+		t.Fatal("expected an error when an event is recorded before the game header")
+	}
+}
@@ -0,0 +1,286 @@
+package bgammon
+
+import (
+	"math"
+	"math/rand"
+)
+
+// DiceRoller provides dice rolls to simulated games.
+type DiceRoller interface {
+	Roll() int8 // Roll returns a value from 1 to 6.
+}
+
+type seededRoller struct {
+	r *rand.Rand
+}
+
+func (r *seededRoller) Roll() int8 {
+	return int8(r.r.Intn(6) + 1)
+}
+
+// NewSeededRoller returns a DiceRoller which always produces the same rolls
+// when provided the same seed.
+func NewSeededRoller(seed int64) DiceRoller {
+	return &seededRoller{
+		r: rand.New(rand.NewSource(seed)),
+	}
+}
+
+// rolloutTurnLimit is the maximum number of turns played in a simulated game.
+// Games which reach the limit are counted as unfinished.
+const rolloutTurnLimit = 1000
+
+// heuristicScore returns a rough evaluation of the position for the provided
+// player. Higher scores are better.
+func heuristicScore(g *Game, player int8) float64 {
+	score := float64(g.PipCount(opponentNumber(player)) - g.PipCount(player))
+	contact := !g.IsRace()
+	for space := int8(1); space <= 24; space++ {
+		checkers := PlayerCheckers(g.Board[space], player)
+		if checkers >= 2 {
+			score += 3
+		} else if checkers == 1 && contact {
+			score -= 4
+		}
+	}
+	return score
+}
+
+// rolloutGame simulates the remainder of the game, choosing moves using the
+// provided policy, and returns the winner and win type. A winner of 0 is
+// returned when the turn limit is reached. The special rolls of acey-deucey
+// are not simulated.
+func rolloutGame(g *Game, roller DiceRoller, policy MovePolicy) (winner int8, winType int8) {
+	gc := g.Copy(false)
+	for turns := 0; gc.Winner == 0 && gc.Turn != 0 && turns < rolloutTurnLimit; turns++ {
+		if gc.Roll1 == 0 {
+			gc.Roll1, gc.Roll2 = roller.Roll(), roller.Roll()
+			if gc.Variant == VariantTabula {
+				gc.Roll3 = roller.Roll()
+			}
+		}
+		for _, move := range policy.ChooseMoves(gc, false) {
+			gc.addMove(move)
+		}
+		if gc.PipCount(gc.Turn) == 0 {
+			gc.Winner = gc.Turn
+			break
+		}
+		gc.NextTurn(false)
+	}
+	return gc.Winner, gc.WinType()
+}
+
+// GammonChances returns how often the provided player wins and loses by a
+// gammon or backgammon when the remainder of the game is simulated the
+// provided number of times. The game must be in progress. The chances are the
+// same from either perspective, so local is ignored.
+func (g *Game) GammonChances(player int8, trials int, roller DiceRoller, local bool) (winGammon float64, loseGammon float64) {
+	if trials <= 0 || g.Turn == 0 {
+		return 0, 0
+	}
+	var wins, losses int
+	for i := 0; i < trials; i++ {
+		winner, winType := rolloutGame(g, roller, HeuristicPolicy{})
+		if winType < 2 {
+			continue
+		} else if winner == player {
+			wins++
+		} else {
+			losses++
+		}
+	}
+	return float64(wins) / float64(trials), float64(losses) / float64(trials)
+}
+
+// Rollout returns the average number of points won by the provided player,
+// without considering the doubling cube, when the remainder of the game is
+// simulated the provided number of times. Moves are chosen using the provided
+// policy, or HeuristicPolicy when no policy is provided.
+func Rollout(g *Game, player int8, trials int, roller DiceRoller, policy MovePolicy) float64 {
+	if trials <= 0 || g.Turn == 0 {
+		return 0
+	}
+	if policy == nil {
+		policy = HeuristicPolicy{}
+	}
+	var points int
+	for i := 0; i < trials; i++ {
+		winner, winType := rolloutGame(g, roller, policy)
+		if winner == player {
+			points += int(winType)
+		} else if winner != 0 {
+			points -= int(winType)
+		}
+	}
+	return float64(points) / float64(trials)
+}
+
+// TooGoodToDouble returns whether the provided player is expected to win more
+// than a single point by playing on, taking gammons and backgammons into
+// account. Doubling in such a position allows the opponent to pass and
+// concede only a single point. Local is ignored, as the player is specified by
+// number.
+func (g *Game) TooGoodToDouble(player int8, trials int, roller DiceRoller, local bool) bool {
+	if g.Variant != VariantBackgammon {
+		return false
+	}
+	return Rollout(g, player, trials, roller, HeuristicPolicy{}) > 1
+}
+
+// IsCash returns whether the provided player is expected to win enough points
+// by playing on that the opponent should pass a double, without being so far
+// ahead that the player should play on for a gammon instead. This is the
+// window where ShouldTake returns false for the opponent and TooGoodToDouble
+// returns false for the player. The result does not depend on perspective, so
+// local is ignored.
+func (g *Game) IsCash(player int8, trials int, roller DiceRoller, local bool) bool {
+	if g.Variant != VariantBackgammon || g.NoCube {
+		return false
+	}
+	equity := Rollout(g, player, trials, roller, HeuristicPolicy{})
+	return -equity < takePoint && equity <= 1
+}
+
+// BearOffRace returns how often each player wins when the remainder of a race
+// is simulated the provided number of times, starting with the player on roll.
+// Moves are chosen using BestBearOffPolicy. -1 is returned for both players
+// when the game is not in progress or the players' checkers are still in
+// contact.
+func (g *Game) BearOffRace(trials int, roller DiceRoller) (float64, float64) {
+	if trials <= 0 || g.Turn == 0 || !g.IsRace() {
+		return -1, -1
+	}
+	var wins1, wins2 int
+	for i := 0; i < trials; i++ {
+		winner, _ := rolloutGame(g, roller, BestBearOffPolicy{})
+		switch winner {
+		case 1:
+			wins1++
+		case 2:
+			wins2++
+		}
+	}
+	return float64(wins1) / float64(trials), float64(wins2) / float64(trials)
+}
+
+// takePoint is the minimum cubeless equity required to accept a double in a
+// money game. Taking doubles the stake, so a player taking at this equity
+// loses the same amount as a player who passes.
+const takePoint = -0.5
+
+// ShouldTake returns whether the provided player should accept a double
+// offered by their opponent, taking gammons and backgammons into account.
+// Match scores are not considered, so the take point of a money game is
+// always used. Local is ignored because the decision is the same from either
+// perspective.
+func (g *Game) ShouldTake(player int8, trials int, roller DiceRoller, local bool) bool {
+	return Rollout(g, player, trials, roller, HeuristicPolicy{}) >= takePoint
+}
+
+// JokerThreshold is the difference in equity from the average roll required
+// for a roll to be returned by JokerRolls.
+var JokerThreshold = 0.5
+
+// jokerTrials is the number of games simulated after each roll is played when
+// searching for joker rolls.
+const jokerTrials = 100
+
+// JokerRolls returns the rolls which change the provided player's rollout
+// equity by more than JokerThreshold compared to the average roll, when each
+// roll is played using HeuristicPolicy. The game's current dice and pending
+// moves are ignored. The higher die of each roll is listed first. Nil is
+// returned in tabula games. Rolls are the same from either perspective, so
+// local is ignored.
+func (g *Game) JokerRolls(player int8, roller DiceRoller, local bool) [][2]int8 {
+	if g.Variant == VariantTabula || g.Turn == 0 || g.Winner != 0 {
+		return nil
+	}
+	type rollEquity struct {
+		roll   [2]int8
+		equity float64
+	}
+	var rolls []rollEquity
+	var total float64
+	eachRoll(g.Variant, func(roll1 int8, roll2 int8, roll3 int8, outcomes int) {
+		gc := g.Copy(true)
+		gc.Turn = player
+		gc.Roll1, gc.Roll2, gc.Roll3 = roll1, roll2, 0
+		gc.Moves = nil
+		for _, move := range (HeuristicPolicy{}).ChooseMoves(gc, false) {
+			gc.addMove(move)
+		}
+		equity := Rollout(gc, player, jokerTrials, roller, HeuristicPolicy{})
+		total += equity * float64(outcomes)
+		if roll2 > roll1 {
+			roll1, roll2 = roll2, roll1
+		}
+		rolls = append(rolls, rollEquity{
+			roll:   [2]int8{roll1, roll2},
+			equity: equity,
+		})
+	})
+	average := total / float64(rollOutcomes(g.Variant))
+	var jokers [][2]int8
+	for _, r := range rolls {
+		if math.Abs(r.equity-average) > JokerThreshold {
+			jokers = append(jokers, r.roll)
+		}
+	}
+	return jokers
+}
+
+// PlayRandomGame plays a complete game of the provided variant, rolling dice
+// using the provided roller and choosing moves using the provided policy, and
+// returns a replay of the game. Games which reach the turn limit are returned
+// without a winner. The special rolls of acey-deucey are not simulated.
+func PlayRandomGame(variant int8, roller DiceRoller, policy MovePolicy) *Replay {
+	g := NewGame(variant)
+	g.Player1.Name, g.Player2.Name = "Player 1", "Player 2"
+	for g.Roll1 == g.Roll2 {
+		g.Roll1, g.Roll2 = roller.Roll(), roller.Roll()
+	}
+	g.Turn = 1
+	if g.Roll2 > g.Roll1 {
+		g.Turn = 2
+	}
+	if variant != VariantBackgammon {
+		g.Roll1, g.Roll2 = 0, 0
+	}
+
+	game := &ReplayGame{
+		Player1:     g.Player1.Name,
+		Player2:     g.Player2.Name,
+		Points:      g.Points,
+		DoubleValue: g.DoubleValue,
+		Variant:     variant,
+	}
+	for turns := 0; turns < rolloutTurnLimit; turns++ {
+		if g.Roll1 == 0 {
+			g.Roll1, g.Roll2 = roller.Roll(), roller.Roll()
+			if variant == VariantTabula {
+				g.Roll3 = roller.Roll()
+			}
+		}
+		for _, move := range policy.ChooseMoves(g, false) {
+			g.addMove(move)
+		}
+		ev := &ReplayEvent{
+			Player: g.Turn,
+			Type:   ReplayEventRoll,
+			Roll:   [3]int8{g.Roll1, g.Roll2, g.Roll3},
+			Moves:  make([][]int8, len(g.Moves)),
+		}
+		copy(ev.Moves, g.Moves)
+		game.Events = append(game.Events, ev)
+		if g.PipCount(g.Turn) == 0 {
+			g.Winner = g.Turn
+			break
+		}
+		g.NextTurn(false)
+	}
+	game.Winner = g.Winner
+	return &Replay{
+		Games: []*ReplayGame{game},
+	}
+}
@@ -13,3 +13,15 @@ func maxInt(a int8, b int8) int8 {
 	}
 	return a
 }
+
+func equalBoards(a []int8, b []int8) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}